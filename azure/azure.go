@@ -0,0 +1,807 @@
+// Bifrost interface for Azure Blob Storage
+package azure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/pool"
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/stream"
+	"github.com/opensaucerer/bifrost/shared/types"
+	"github.com/opensaucerer/bifrost/shared/walk"
+)
+
+/*
+UploadFile uploads a file to Azure Blob Storage and returns an error if one occurs.
+
+Note: UploadFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (a *AzureBlobStorage) UploadFile(fileFace interface{}) (*types.UploadedFile, error) {
+	return a.UploadFileContext(context.Background(), fileFace)
+}
+
+// UploadFileContext is like UploadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (a *AzureBlobStorage) UploadFileContext(ctx context.Context, fileFace interface{}) (*types.UploadedFile, error) {
+	bFile, err := types.AsFile(fileFace)
+	if err != nil {
+		return nil, err
+	}
+	return a.Upload(ctx, bFile)
+}
+
+// Upload uploads a file to Azure Blob Storage and returns an error if one occurs. It is the typed equivalent of UploadFileContext.
+// Uploads failing with a transient error are retried according to the bridge RetryPolicy.
+func (a *AzureBlobStorage) Upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	var uploadedFile *types.UploadedFile
+	err := retry.Upload(ctx, a.RetryPolicy, a.IsRetryable, bFile, func() error {
+		var err error
+		uploadedFile, err = a.upload(ctx, bFile)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uploadedFile, nil
+}
+
+/*
+UploadJSON marshals doc.Data and uploads it to Azure Blob Storage as a JSON document with the application/json content type, unless
+another content type is set in doc.Options, and returns an error if one occurs.
+
+Note: UploadJSON requires doc.Filename.
+*/
+func (a *AzureBlobStorage) UploadJSON(ctx context.Context, doc types.JSONFile) (*types.UploadedFile, error) {
+	bFile, err := doc.File()
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+	return a.Upload(ctx, bFile)
+}
+
+// upload makes a single attempt at uploading a file to Azure Blob Storage.
+func (a *AzureBlobStorage) upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	// validate struct
+	if err := bFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !a.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Azure Blob Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if a.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(a.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	if bFile.Path != "" {
+		// verify that file exists
+		if _, err := os.Stat(bFile.Path); os.IsNotExist(err) {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s", bFile.Path),
+				ErrorCode: errors.ErrBadRequest,
+			}
+		}
+		// open file
+		file, err := os.Open(bFile.Path)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+		// close file
+		defer file.Close()
+
+		bFile.Handle = file
+
+		// ensure filename
+		if bFile.Filename == "" {
+			bFile.Filename = filepath.Base(bFile.Path)
+		}
+	}
+
+	params := &blobInput{
+		Bucket: a.DefaultBucket,
+		Name:   bFile.Filename,
+		// report progress as the SDK reads the body
+		Body: progress.Reader(bFile.Handle, bFile),
+	}
+	// work on a copy of the options so that the caller's map is never written to
+	bFile = bFile.Clone()
+
+	// if no ACL is set, check if a.PublicRead is true
+	if bFile.Options[config.OptACL] == nil && a.PublicRead {
+		// set public read permissions
+		bFile.Options[config.OptACL] = config.ACLPublicRead
+	}
+	// configure upload options
+	for k, v := range bFile.Options {
+		switch k {
+		// Azure has no object ACLs, so the ACL is applied to the container
+		case config.OptACL:
+			if v, ok := v.(string); ok {
+				switch v {
+				case config.ACLPublicRead:
+					access := container.PublicAccessTypeBlob
+					params.Access = &access
+					params.SetAccess = true
+				case config.ACLPrivate:
+					params.SetAccess = true
+				}
+			}
+		// set the content type
+		case config.OptContentType:
+			if v, ok := v.(string); ok {
+				params.HTTPHeaders.BlobContentType = &v
+			}
+		// set blob metadata
+		case config.OptMetadata:
+			if v, ok := v.(map[string]string); ok {
+				params.Metadata = make(map[string]*string, len(v))
+				for mk, mv := range v {
+					mv := mv
+					params.Metadata[mk] = &mv
+				}
+			}
+		}
+	}
+
+	if params.SetAccess {
+		if err := a.setContainerAccess(ctx, params.Bucket, params.Access); err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+	}
+	// Upload the file to Azure, in blocks if it is large or of unknown length
+	if err := a.putBlob(ctx, params); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	// blob properties
+	props, err := a.blobClient(a.DefaultBucket, bFile.Filename).GetProperties(ctx, nil)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return &types.UploadedFile{
+		Name:           bFile.Filename,
+		Bucket:         a.DefaultBucket,
+		Path:           bFile.Path,
+		Preview:        a.objectURL(a.DefaultBucket, bFile.Filename),
+		Size:           deref(props.ContentLength),
+		ProviderObject: props,
+		URL:            a.objectURL(a.DefaultBucket, bFile.Filename),
+	}, nil
+}
+
+// UploadMultiFile
+func (a *AzureBlobStorage) UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error) {
+	return a.UploadMultiFileContext(context.Background(), multiFace)
+}
+
+// UploadMultiFileContext is like UploadMultiFile but uses ctx for cancellation and deadlines of every upload.
+func (a *AzureBlobStorage) UploadMultiFileContext(ctx context.Context, multiFace interface{}) ([]*types.UploadedFile, error) {
+	multiFile, err := types.AsMultiFile(multiFace)
+	if err != nil {
+		return nil, err
+	}
+	return a.UploadMulti(ctx, multiFile)
+}
+
+// UploadMulti uploads multiple files to Azure Blob Storage and returns an error if one occurs. It is the typed equivalent of UploadMultiFileContext.
+func (a *AzureBlobStorage) UploadMulti(ctx context.Context, multiFile types.MultiFile) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := multiFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !a.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Azure Blob Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+
+	uploadedFiles := make([]*types.UploadedFile, len(multiFile.Files))
+	batch := progress.NewBatch(multiFile)
+
+	// upload concurrently when UseAsync is true, writing each result at its file's index to keep the order of multiFile.Files
+	pool.Run(len(multiFile.Files), pool.Size(a.UseAsync, a.MaxConcurrency), func(i int) {
+		// merge global options into a copy of the file options so that shared maps are never written to
+		file := batch.Track(i, multiFile.Merge(i))
+
+		uploadedFile, err := a.Upload(ctx, file)
+		batch.Done(i, err)
+		if err != nil {
+			if a.EnableDebug {
+				// log failed file and continue
+				log.Printf("Upload for file at path %s failed with err: %s\n", file.Path, err.Error())
+			}
+			uploadedFiles[i] = &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path}
+			return
+		}
+		uploadedFiles[i] = uploadedFile
+	})
+
+	return uploadedFiles, nil
+}
+
+// Config returns the Azure Blob Storage configuration.
+func (a *AzureBlobStorage) Config() *types.BridgeConfig {
+	return &types.BridgeConfig{
+		DefaultBucket:      a.DefaultBucket,
+		AccessKey:          a.AccountName,
+		SecretKey:          a.AccountKey,
+		SASToken:           a.SASToken,
+		ConnectionString:   a.ConnectionString,
+		Endpoint:           a.Endpoint,
+		BaseURL:            a.BaseURL,
+		DefaultTimeout:     a.DefaultTimeout,
+		EnableDebug:        a.EnableDebug,
+		Provider:           a.Provider,
+		PublicRead:         a.PublicRead,
+		UseAsync:           a.UseAsync,
+		MaxConcurrency:     a.MaxConcurrency,
+		RetryPolicy:        a.RetryPolicy,
+		MultipartThreshold: a.MultipartThreshold,
+		PartSize:           a.PartSize,
+		PartConcurrency:    a.PartConcurrency,
+	}
+}
+
+/*
+Disconnect closes the Azure Blob Storage connection and returns an error if one occurs.
+
+Disconnect should only be called when the connection is no longer needed.
+*/
+func (a *AzureBlobStorage) Disconnect() error {
+	if a.IsConnected() {
+		a.Client = nil
+	}
+	return nil
+}
+
+// IsConnected returns true if the Azure Blob Storage connection is open.
+func (a *AzureBlobStorage) IsConnected() bool {
+	return a.Client != nil
+}
+
+// Ping checks that the default Azure container can be reached with the configured credentials and returns an error if one occurs.
+func (a *AzureBlobStorage) Ping(ctx context.Context) error {
+	if !a.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active Azure Blob Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if a.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(a.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	if _, err := a.Client.ServiceClient().NewContainerClient(a.DefaultBucket).GetProperties(ctx, nil); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	return nil
+}
+
+/*
+UploadFolder uploads every file in a local folder to Azure Blob Storage and returns an error if one occurs.
+Each file is stored under its path relative to Folder.Path, behind Folder.Prefix, and Folder.Options are merged into
+each file's options the same way as MultiFile.GlobalOptions. Like UploadMultiFile, one UploadedFile is returned per file with
+any failure recorded in its Error field.
+
+Note: UploadFolder requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (a *AzureBlobStorage) UploadFolder(foldFace interface{}) ([]*types.UploadedFile, error) {
+	return a.UploadFolderContext(context.Background(), foldFace)
+}
+
+// UploadFolderContext is like UploadFolder but uses ctx for cancellation and deadlines of every upload.
+func (a *AzureBlobStorage) UploadFolderContext(ctx context.Context, foldFace interface{}) ([]*types.UploadedFile, error) {
+	folder, err := types.AsFolder(foldFace)
+	if err != nil {
+		return nil, err
+	}
+	return a.UploadDir(ctx, folder)
+}
+
+// UploadDir uploads every file in a local folder to Azure Blob Storage and returns an error if one occurs. It is the typed equivalent of UploadFolderContext.
+func (a *AzureBlobStorage) UploadDir(ctx context.Context, folder types.Folder) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := folder.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	files, err := walk.Files(folder)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if len(files) == 0 {
+		return []*types.UploadedFile{}, nil
+	}
+
+	return a.UploadMulti(ctx, types.MultiFile{
+		Files:         files,
+		GlobalOptions: folder.Options,
+	})
+}
+
+/*
+DeleteFile deletes a file from Azure Blob Storage and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
+
+Note: DeleteFile requires that a default bucket be set in bifrost.BridgeConfig unless DeleteFile.Buckets is set.
+*/
+func (a *AzureBlobStorage) DeleteFile(fileFace interface{}) error {
+	return a.DeleteFileContext(context.Background(), fileFace)
+}
+
+// DeleteFileContext is like DeleteFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (a *AzureBlobStorage) DeleteFileContext(ctx context.Context, fileFace interface{}) error {
+	dFile, err := types.AsDeleteFile(fileFace)
+	if err != nil {
+		return err
+	}
+	return a.Delete(ctx, dFile)
+}
+
+// Delete deletes a file from Azure Blob Storage and returns an error if one occurs. It is the typed equivalent of DeleteFileContext.
+func (a *AzureBlobStorage) Delete(ctx context.Context, dFile types.DeleteFile) error {
+	// validate struct
+	if err := dFile.Validate(); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+	// files are stored by name, so a CID alone does not identify one
+	if dFile.Filename == "" {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("file.Filename is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !a.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active Azure Blob Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if a.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(a.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	buckets := dFile.Buckets
+	if len(buckets) == 0 {
+		buckets = []string{a.DefaultBucket}
+	}
+
+	for _, bucket := range buckets {
+		if _, err := a.Client.DeleteBlob(ctx, bucket, dFile.Filename, nil); err != nil {
+			if isNotFound(err) {
+				return &errors.BifrostError{
+					Err:       fmt.Errorf("file does not exist: %s/%s", bucket, dFile.Filename),
+					ErrorCode: errors.ErrNotFound,
+				}
+			}
+			return &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+	}
+	return nil
+}
+
+/*
+OpenReader opens a file in Azure Blob Storage for reading and returns its contents along with its attributes.
+If the file does not exist, an error with the code ErrNotFound is returned.
+The caller must close the returned reader.
+
+Note: OpenReader requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (a *AzureBlobStorage) OpenReader(name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	return a.OpenReaderContext(context.Background(), name)
+}
+
+// OpenReaderContext is like OpenReader but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx
+// and covers reading the returned reader.
+func (a *AzureBlobStorage) OpenReaderContext(ctx context.Context, name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	if name == "" {
+		return nil, nil, &errors.BifrostError{
+			Err:       fmt.Errorf("name is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !a.IsConnected() {
+		return nil, nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Azure Blob Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// the context must outlive this call as the body is read by the caller, so it is cancelled when the reader is closed
+	var cancel context.CancelFunc
+	if a.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(a.DefaultTimeout)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	obj, err := a.Client.DownloadStream(ctx, a.DefaultBucket, name, nil)
+	if err != nil {
+		cancel()
+		if isNotFound(err) {
+			return nil, nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s/%s", a.DefaultBucket, name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	return stream.CancelOnClose(obj.Body, cancel), &types.ObjectInfo{
+		Name:           name,
+		Bucket:         a.DefaultBucket,
+		Size:           deref(obj.ContentLength),
+		ContentType:    deref(obj.ContentType),
+		Metadata:       metadata(obj.Metadata),
+		ETag:           etag(obj.ETag),
+		Updated:        deref(obj.LastModified),
+		URL:            a.objectURL(a.DefaultBucket, name),
+		ProviderObject: obj,
+	}, nil
+}
+
+/*
+DownloadFile downloads a file from Azure Blob Storage to localPath and returns its attributes.
+localPath is only replaced once the whole file has been downloaded.
+
+Note: DownloadFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (a *AzureBlobStorage) DownloadFile(name, localPath string) (*types.ObjectInfo, error) {
+	return a.DownloadFileContext(context.Background(), name, localPath)
+}
+
+// DownloadFileContext is like DownloadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (a *AzureBlobStorage) DownloadFileContext(ctx context.Context, name, localPath string) (*types.ObjectInfo, error) {
+	if localPath == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("localPath is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	rc, info, err := a.OpenReaderContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if info.Size, err = stream.SaveFile(localPath, rc); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return info, nil
+}
+
+/*
+ListFiles lists the files in the default Azure container one page at a time and returns an error if one occurs.
+Pass ListResult.NextToken as ListOptions.ContinuationToken to list the following page.
+
+Note: ListFiles requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (a *AzureBlobStorage) ListFiles(opts types.ListOptions) (*types.ListResult, error) {
+	return a.ListFilesContext(context.Background(), opts)
+}
+
+// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (a *AzureBlobStorage) ListFilesContext(ctx context.Context, opts types.ListOptions) (*types.ListResult, error) {
+
+	// validate struct
+	if err := opts.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !a.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Azure Blob Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if a.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(a.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	params := container.ListBlobsHierarchyOptions{
+		Include: container.ListBlobsInclude{Metadata: true},
+	}
+	if opts.Prefix != "" {
+		params.Prefix = &opts.Prefix
+	}
+	if opts.ContinuationToken != "" {
+		params.Marker = &opts.ContinuationToken
+	}
+	if opts.PageSize > 0 {
+		size := int32(opts.PageSize)
+		params.MaxResults = &size
+	}
+
+	var (
+		items    []*container.BlobItem
+		prefixes []*container.BlobPrefix
+		next     *string
+	)
+	client := a.Client.ServiceClient().NewContainerClient(a.DefaultBucket)
+	if opts.Delimiter != "" {
+		// only the hierarchy listing groups names into prefixes
+		out, err := client.NewListBlobsHierarchyPager(opts.Delimiter, &params).NextPage(ctx)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+		if out.Segment != nil {
+			items, prefixes = out.Segment.BlobItems, out.Segment.BlobPrefixes
+		}
+		next = out.NextMarker
+	} else {
+		out, err := client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+			Include:    params.Include,
+			Prefix:     params.Prefix,
+			Marker:     params.Marker,
+			MaxResults: params.MaxResults,
+		}).NextPage(ctx)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+		if out.Segment != nil {
+			items = out.Segment.BlobItems
+		}
+		next = out.NextMarker
+	}
+
+	result := &types.ListResult{
+		Files:     make([]*types.ObjectInfo, 0, len(items)),
+		NextToken: deref(next),
+	}
+	for _, item := range items {
+		info := &types.ObjectInfo{
+			Name:           deref(item.Name),
+			Bucket:         a.DefaultBucket,
+			Metadata:       metadata(item.Metadata),
+			URL:            a.objectURL(a.DefaultBucket, deref(item.Name)),
+			ProviderObject: item,
+		}
+		if item.Properties != nil {
+			info.Size = deref(item.Properties.ContentLength)
+			info.ContentType = deref(item.Properties.ContentType)
+			info.ETag = etag(item.Properties.ETag)
+			info.Created = deref(item.Properties.CreationTime)
+			info.Updated = deref(item.Properties.LastModified)
+		}
+		result.Files = append(result.Files, info)
+	}
+	for _, prefix := range prefixes {
+		result.Prefixes = append(result.Prefixes, deref(prefix.Name))
+	}
+	return result, nil
+}
+
+/*
+StatFile returns the attributes of a file in the default Azure container and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
+
+Note: StatFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (a *AzureBlobStorage) StatFile(name string) (*types.ObjectInfo, error) {
+	return a.StatFileContext(context.Background(), name)
+}
+
+// StatFileContext is like StatFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (a *AzureBlobStorage) StatFileContext(ctx context.Context, name string) (*types.ObjectInfo, error) {
+	if name == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("name is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !a.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Azure Blob Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if a.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(a.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	props, err := a.blobClient(a.DefaultBucket, name).GetProperties(ctx, nil)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s/%s", a.DefaultBucket, name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	return &types.ObjectInfo{
+		Name:           name,
+		Bucket:         a.DefaultBucket,
+		Size:           deref(props.ContentLength),
+		ContentType:    deref(props.ContentType),
+		Metadata:       metadata(props.Metadata),
+		ETag:           etag(props.ETag),
+		Created:        deref(props.CreationTime),
+		Updated:        deref(props.LastModified),
+		URL:            a.objectURL(a.DefaultBucket, name),
+		ProviderObject: props,
+	}, nil
+}
+
+/*
+Exists returns true if a file exists in the default Azure container and returns an error if the lookup fails.
+
+Note: Exists requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (a *AzureBlobStorage) Exists(name string) (bool, error) {
+	return a.ExistsContext(context.Background(), name)
+}
+
+// ExistsContext is like Exists but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (a *AzureBlobStorage) ExistsContext(ctx context.Context, name string) (bool, error) {
+	if _, err := a.StatFileContext(ctx, name); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// IsRetryable returns true if err is a transient Azure failure, such as a throttled request, a 5xx response or a dropped
+// connection, that is worth retrying.
+func (a *AzureBlobStorage) IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var re *azcore.ResponseError
+	if errors.As(err, &re) {
+		return retry.IsRetryableStatus(re.StatusCode)
+	}
+	return retry.IsTransient(err)
+}
+
+// blobClient returns a client for the blob stored as name in bucket.
+func (a *AzureBlobStorage) blobClient(bucket, name string) *blob.Client {
+	return a.Client.ServiceClient().NewContainerClient(bucket).NewBlobClient(name)
+}
+
+// isNotFound returns true if err reports a missing blob or container.
+func isNotFound(err error) bool {
+	if bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound, bloberror.ResourceNotFound) {
+		return true
+	}
+	var re *azcore.ResponseError
+	return errors.As(err, &re) && re.StatusCode == http.StatusNotFound
+}
+
+/*
+objectURL returns the public URL of the blob stored as name in bucket.
+
+The URL is built from BaseURL when it is set and from the blob service endpoint otherwise.
+*/
+func (a *AzureBlobStorage) objectURL(bucket, name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	path := strings.Join(segments, "/")
+
+	if a.BaseURL != "" {
+		return strings.TrimSuffix(a.BaseURL, "/") + "/" + path
+	}
+	return strings.TrimSuffix(a.Endpoint, "/") + "/" + url.PathEscape(bucket) + "/" + path
+}
+
+// metadata converts Azure blob metadata to a plain map.
+func metadata(m map[string]*string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = deref(v)
+	}
+	return out
+}
+
+// etag returns the unquoted value of an Azure ETag.
+func etag(e *azcore.ETag) string {
+	if e == nil {
+		return ""
+	}
+	return strings.Trim(string(*e), `"`)
+}
+
+// deref returns the value p points to or the zero value when p is nil.
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
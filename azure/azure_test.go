@@ -0,0 +1,285 @@
+package azure_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opensaucerer/bifrost"
+)
+
+var (
+	bridge bifrost.RainbowBridge
+	err    error
+
+	AZURE_STORAGE_ACCOUNT   = os.Getenv("AZURE_STORAGE_ACCOUNT")
+	AZURE_STORAGE_KEY       = os.Getenv("AZURE_STORAGE_KEY")
+	AZURE_STORAGE_CONTAINER = os.Getenv("AZURE_STORAGE_CONTAINER")
+	// set these to run the tests against the Azurite emulator, e.g. http://127.0.0.1:10000/devstoreaccount1
+	AZURE_STORAGE_ENDPOINT          = os.Getenv("AZURE_STORAGE_ENDPOINT")
+	AZURE_STORAGE_CONNECTION_STRING = os.Getenv("AZURE_STORAGE_CONNECTION_STRING")
+)
+
+func setup(t *testing.T) {
+	if AZURE_STORAGE_CONNECTION_STRING == "" && AZURE_STORAGE_ACCOUNT == "" {
+		t.Skip("set AZURE_STORAGE_CONNECTION_STRING or AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_KEY to run the Azure tests")
+	}
+
+	bridge, err = bifrost.NewRainbowBridge(&bifrost.BridgeConfig{
+		DefaultBucket:    AZURE_STORAGE_CONTAINER,
+		DefaultTimeout:   10,
+		Provider:         bifrost.AzureBlobStorage,
+		EnableDebug:      true,
+		PublicRead:       true,
+		AccessKey:        AZURE_STORAGE_ACCOUNT,
+		SecretKey:        AZURE_STORAGE_KEY,
+		Endpoint:         AZURE_STORAGE_ENDPOINT,
+		ConnectionString: AZURE_STORAGE_CONNECTION_STRING,
+	})
+	if err != nil {
+		t.Error(err.(bifrost.Error).Code(), err)
+		return
+	}
+
+	t.Logf("Connected to %s\n", bridge.Config().Provider)
+}
+
+func teardown() {
+	bridge.Disconnect()
+}
+
+func TestAzure(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	t.Run("Tests UploadFile method", func(t *testing.T) {
+		o, err := bridge.UploadFile(bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+			Options: map[string]interface{}{
+				bifrost.OptMetadata: map[string]string{
+					"originalname": "aand.png",
+				},
+			},
+		})
+		if err != nil {
+			t.Errorf("Failed to upload file: %v", err)
+			return
+		}
+		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
+	})
+
+	t.Run("Tests Upload method", func(t *testing.T) {
+		o, err := bridge.Upload(context.Background(), bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+		})
+		if err != nil {
+			t.Errorf("Failed to upload file: %v", err)
+			return
+		}
+		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
+
+		// the untyped methods also accept pointers
+		if _, err := bridge.UploadFile(&bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+		}); err != nil {
+			t.Errorf("Failed to upload file from pointer: %v", err)
+		}
+	})
+
+	t.Run("Tests UploadFile method with a non-seekable handle", func(t *testing.T) {
+		f, err := os.Open("../shared/image/aand.png")
+		if err != nil {
+			t.Errorf("Failed to open file: %v", err)
+			return
+		}
+		defer f.Close()
+
+		// a reader of unknown length is staged in blocks
+		o, err := bridge.UploadFile(bifrost.File{
+			Handle:   io.MultiReader(f),
+			Filename: "a_and_ampersand_stream.png",
+		})
+		if err != nil {
+			t.Errorf("Failed to upload file: %v", err)
+			return
+		}
+		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
+	})
+
+	t.Run("Tests UploadFile method with progress reporting", func(t *testing.T) {
+		var last bifrost.Progress
+		o, err := bridge.UploadFile(bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+			Progress: func(p bifrost.Progress) {
+				last = p
+			},
+		})
+		if err != nil {
+			t.Errorf("Failed to upload file: %v", err)
+			return
+		}
+		if last.Total <= 0 || last.Bytes != last.Total {
+			t.Errorf("Expected progress to reach the file size, got %d of %d bytes", last.Bytes, last.Total)
+		}
+		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
+	})
+
+	t.Run("Tests UploadMultiFile method", func(t *testing.T) {
+		o, err := bridge.UploadMultiFile(bifrost.MultiFile{
+			Files: []bifrost.File{
+				{
+					Path:     "../shared/image/aand.png",
+					Filename: "a_and_ampersand.png",
+					Options: map[string]interface{}{
+						bifrost.OptMetadata: map[string]string{
+							"originalname": "aand.png",
+						},
+						bifrost.OptACL: bifrost.ACLPublicRead,
+					},
+				},
+				{
+					Path:     "../shared/image/bifrost.webp",
+					Filename: "bifrost_bridge.webp",
+					Options: map[string]interface{}{
+						bifrost.OptMetadata: map[string]string{
+							"originalname": "bifrost.jpg",
+							"universe":     "Marvel",
+						},
+					},
+				},
+			},
+
+			// say 3 of 4 files need to share the same option, you can set globally for those 3 files and set the 4th file's option separately, bifrost won't override the option
+			GlobalOptions: map[string]interface{}{
+				bifrost.OptACL: bifrost.ACLPrivate,
+			},
+		})
+		if err != nil {
+			t.Errorf("Failed to upload file: %v", err)
+			return
+		}
+
+		for _, file := range o {
+			t.Logf("Uploaded file: %s to %s\n", file.Name, file.Preview)
+		}
+	})
+
+	t.Run("Tests UploadFolder method", func(t *testing.T) {
+		o, err := bridge.UploadFolder(bifrost.Folder{
+			Path:    "../shared/image",
+			Prefix:  "bifrost/images/",
+			Exclude: []string{"*.webp"},
+			Options: map[string]interface{}{
+				bifrost.OptMetadata: map[string]string{
+					"folder": "image",
+				},
+			},
+		})
+		if err != nil {
+			t.Errorf("Failed to upload folder: %v", err)
+			return
+		}
+
+		for _, file := range o {
+			if file.Error != nil {
+				t.Errorf("Failed to upload file %s: %v", file.Name, file.Error)
+				continue
+			}
+			t.Logf("Uploaded file: %s to %s\n", file.Name, file.Preview)
+		}
+	})
+
+	t.Run("Tests UploadFileContext method with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := bridge.UploadFileContext(ctx, bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+		})
+		if err == nil {
+			t.Errorf("Expected upload with a cancelled context to fail")
+			return
+		}
+		t.Logf("Upload with a cancelled context failed with: %v\n", err)
+	})
+
+	t.Run("Tests DownloadFile method", func(t *testing.T) {
+		localPath := filepath.Join(t.TempDir(), "a_and_ampersand.png")
+		o, err := bridge.DownloadFile("a_and_ampersand.png", localPath)
+		if err != nil {
+			t.Errorf("Failed to download file: %v", err)
+			return
+		}
+		t.Logf("Downloaded file: %s (%d bytes) to %s\n", o.Name, o.Size, localPath)
+	})
+
+	t.Run("Tests ListFiles method", func(t *testing.T) {
+		opts := bifrost.ListOptions{
+			Prefix:   "bifrost/",
+			PageSize: 1,
+		}
+		for {
+			o, err := bridge.ListFiles(opts)
+			if err != nil {
+				t.Errorf("Failed to list files: %v", err)
+				return
+			}
+			for _, file := range o.Files {
+				t.Logf("Listed file: %s (%d bytes)\n", file.Name, file.Size)
+			}
+			if o.NextToken == "" {
+				break
+			}
+			opts.ContinuationToken = o.NextToken
+		}
+	})
+
+	t.Run("Tests StatFile and Exists methods", func(t *testing.T) {
+		o, err := bridge.StatFile("a_and_ampersand.png")
+		if err != nil {
+			t.Errorf("Failed to stat file: %v", err)
+			return
+		}
+		t.Logf("Stat file: %s (%d bytes, %s)\n", o.Name, o.Size, o.ContentType)
+
+		exists, err := bridge.Exists("does_not_exist.png")
+		if err != nil {
+			t.Errorf("Failed to check file: %v", err)
+			return
+		}
+		if exists {
+			t.Errorf("Expected does_not_exist.png not to exist")
+		}
+
+		if _, err := bridge.StatFile("does_not_exist.png"); !bifrost.IsNotFound(err) {
+			t.Errorf("Expected %s error, got: %v", bifrost.ErrNotFound, err)
+		}
+	})
+
+	t.Run("Tests DeleteFile method", func(t *testing.T) {
+		if err := bridge.DeleteFile(bifrost.DeleteFile{
+			Filename: "bifrost_bridge.webp",
+		}); err != nil {
+			t.Errorf("Failed to delete file: %v", err)
+			return
+		}
+
+		// deleting the same file again should report that it does not exist
+		err := bridge.DeleteFile(bifrost.DeleteFile{
+			Filename: "bifrost_bridge.webp",
+		})
+		if err == nil || err.(bifrost.Error).Code() != bifrost.ErrNotFound {
+			t.Errorf("Expected %s error, got: %v", bifrost.ErrNotFound, err)
+			return
+		}
+		t.Logf("Deleted file: %s\n", "bifrost_bridge.webp")
+	})
+
+}
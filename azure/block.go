@@ -0,0 +1,97 @@
+package azure
+
+import (
+	"context"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/stream"
+)
+
+// blobInput describes a blob to upload.
+type blobInput struct {
+	// Bucket is the container the blob is stored in.
+	Bucket string
+	// Name is the name of the blob.
+	Name string
+	// Body is the content of the blob.
+	Body io.Reader
+	// HTTPHeaders are the HTTP headers the blob is served with.
+	HTTPHeaders blob.HTTPHeaders
+	// Metadata is the user metadata stored with the blob.
+	Metadata map[string]*string
+	// SetAccess is true when the public access level of the container has to be applied.
+	SetAccess bool
+	// Access is the public access level of the container. The container is private when it is nil.
+	Access *container.PublicAccessType
+}
+
+/*
+putBlob uploads params.Body as a block blob.
+
+Seekable bodies smaller than the multipart threshold are sent with a single Put Blob request. Larger bodies and streams of
+unknown length are staged in blocks which are committed once all of them are uploaded, so that only PartSize * PartConcurrency
+bytes are held in memory. Azure garbage collects the uncommitted blocks of a failed upload.
+*/
+func (a *AzureBlobStorage) putBlob(ctx context.Context, params *blobInput) error {
+	client := a.Client.ServiceClient().NewContainerClient(params.Bucket).NewBlockBlobClient(params.Name)
+
+	threshold := a.MultipartThreshold
+	if threshold <= 0 {
+		threshold = config.DefaultMultipartThreshold
+	}
+	if rs, ok := params.Body.(io.ReadSeeker); ok {
+		if size := stream.Size(params.Body); size >= 0 && size < threshold {
+			_, err := client.Upload(ctx, nopCloser{rs}, &blockblob.UploadOptions{
+				HTTPHeaders: &params.HTTPHeaders,
+				Metadata:    params.Metadata,
+			})
+			return err
+		}
+	}
+
+	// zero values fall back to the SDK defaults
+	_, err := client.UploadStream(ctx, params.Body, &blockblob.UploadStreamOptions{
+		BlockSize:   a.PartSize,
+		Concurrency: a.PartConcurrency,
+		HTTPHeaders: &params.HTTPHeaders,
+		Metadata:    params.Metadata,
+	})
+	return err
+}
+
+/*
+setContainerAccess sets the public access level of a container.
+
+Azure only supports public access on whole containers, so it is applied to the container the blob is uploaded to.
+The access policy is only written when the level changes, keeping the stored access policies of the container.
+*/
+func (a *AzureBlobStorage) setContainerAccess(ctx context.Context, bucket string, access *container.PublicAccessType) error {
+	client := a.Client.ServiceClient().NewContainerClient(bucket)
+
+	policy, err := client.GetAccessPolicy(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if deref(policy.BlobPublicAccess) == deref(access) {
+		return nil
+	}
+	_, err = client.SetAccessPolicy(ctx, &container.SetAccessPolicyOptions{
+		Access:       access,
+		ContainerACL: policy.SignedIdentifiers,
+	})
+	return err
+}
+
+// nopCloser is an io.ReadSeekCloser whose Close does nothing, leaving the body to be closed by its owner.
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+// Close does nothing.
+func (nopCloser) Close() error {
+	return nil
+}
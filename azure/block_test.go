@@ -0,0 +1,209 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// fakeRequest is a request received by the fake blob service.
+type fakeRequest struct {
+	Method string
+	Path   string
+	Comp   string
+	Header http.Header
+}
+
+// newFakeBlobService starts a blob service that accepts every upload and records the requests it receives.
+func newFakeBlobService(t *testing.T) (*AzureBlobStorage, func() []fakeRequest) {
+	var (
+		mu       sync.Mutex
+		requests []fakeRequest
+		access   string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		comp := r.URL.Query().Get("comp")
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, fakeRequest{Method: r.Method, Path: r.URL.Path, Comp: comp, Header: r.Header.Clone()})
+
+		w.Header().Set("ETag", `"0x8DB"`)
+		switch {
+		case comp == "acl" && r.Method == http.MethodGet:
+			if access != "" {
+				w.Header().Set("x-ms-blob-public-access", access)
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><SignedIdentifiers />`))
+		case comp == "acl":
+			access = r.Header.Get("x-ms-blob-public-access")
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodHead:
+			w.Header().Set("Content-Length", "11")
+			w.Header().Set("Content-Type", r.Header.Get("x-ms-blob-content-type"))
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cred, err := azblob.NewSharedKeyCredential("devstoreaccount1", "a2V5")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client, err := azblob.NewClientWithSharedKeyCredential(server.URL+"/devstoreaccount1", cred, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return &AzureBlobStorage{
+		DefaultBucket: "bucket",
+		Endpoint:      server.URL + "/devstoreaccount1",
+		Client:        client,
+	}, func() []fakeRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]fakeRequest(nil), requests...)
+	}
+}
+
+func TestUpload(t *testing.T) {
+	t.Run("Tests Upload method maps options onto the blob", func(t *testing.T) {
+		a, requests := newFakeBlobService(t)
+
+		o, err := a.Upload(context.Background(), types.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "dir/a b.txt",
+			Options: map[string]interface{}{
+				config.OptContentType: "text/plain",
+				config.OptMetadata:    map[string]string{"originalname": "a.txt"},
+				config.OptACL:         config.ACLPublicRead,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if o.URL != a.Endpoint+"/bucket/dir/a%20b.txt" {
+			t.Errorf("Unexpected URL %s", o.URL)
+		}
+
+		var put, acl *fakeRequest
+		for _, r := range requests() {
+			r := r
+			switch {
+			case r.Method == http.MethodPut && r.Comp == "acl":
+				acl = &r
+			case r.Method == http.MethodPut:
+				put = &r
+			}
+		}
+		if acl == nil || acl.Header.Get("x-ms-blob-public-access") != "blob" {
+			t.Errorf("Expected the container to be made public, got %+v", acl)
+		}
+		if put == nil {
+			t.Fatalf("Expected the blob to be uploaded in a single request")
+		}
+		if put.Comp != "" {
+			t.Errorf("Expected a single Put Blob request, got comp=%s", put.Comp)
+		}
+		if got := put.Header.Get("x-ms-blob-content-type"); got != "text/plain" {
+			t.Errorf("Expected content type text/plain, got %s", got)
+		}
+		if got := put.Header.Get("x-ms-meta-originalname"); got != "a.txt" {
+			t.Errorf("Expected metadata originalname=a.txt, got %s", got)
+		}
+	})
+
+	t.Run("Tests Upload method only changes the container access when it differs", func(t *testing.T) {
+		a, requests := newFakeBlobService(t)
+
+		for i := 0; i < 2; i++ {
+			if _, err := a.Upload(context.Background(), types.File{
+				Handle:   strings.NewReader("hello world"),
+				Filename: "a.txt",
+				Options:  map[string]interface{}{config.OptACL: config.ACLPublicRead},
+			}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		sets := 0
+		for _, r := range requests() {
+			if r.Method == http.MethodPut && r.Comp == "acl" {
+				sets++
+			}
+		}
+		if sets != 1 {
+			t.Errorf("Expected the access policy to be set once, got %d", sets)
+		}
+	})
+
+	t.Run("Tests Upload method stages blocks for streams of unknown length", func(t *testing.T) {
+		a, requests := newFakeBlobService(t)
+		a.PartSize = 1 << 20
+
+		body := bytes.Repeat([]byte("a"), 3<<20)
+		if _, err := a.Upload(context.Background(), types.File{
+			Handle:   io.MultiReader(bytes.NewReader(body)),
+			Filename: "large.bin",
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		blocks, commits := 0, 0
+		for _, r := range requests() {
+			switch r.Comp {
+			case "block":
+				blocks++
+			case "blocklist":
+				commits++
+			}
+		}
+		if blocks != 3 || commits != 1 {
+			t.Errorf("Expected 3 blocks and 1 commit, got %d blocks and %d commits", blocks, commits)
+		}
+	})
+}
+
+func TestObjectURL(t *testing.T) {
+	cases := []struct {
+		name string
+		a    AzureBlobStorage
+		want string
+	}{
+		{
+			name: "Azure",
+			a:    AzureBlobStorage{Endpoint: "https://account.blob.core.windows.net"},
+			want: "https://account.blob.core.windows.net/bucket/dir/a%20b.png",
+		},
+		{
+			name: "Azurite",
+			a:    AzureBlobStorage{Endpoint: "http://127.0.0.1:10000/devstoreaccount1/"},
+			want: "http://127.0.0.1:10000/devstoreaccount1/bucket/dir/a%20b.png",
+		},
+		{
+			name: "base URL",
+			a:    AzureBlobStorage{Endpoint: "https://account.blob.core.windows.net", BaseURL: "https://cdn.example.com/"},
+			want: "https://cdn.example.com/dir/a%20b.png",
+		},
+	}
+	for _, c := range cases {
+		t.Run("Tests objectURL with "+c.name, func(t *testing.T) {
+			if got := c.a.objectURL("bucket", "dir/a b.png"); got != c.want {
+				t.Errorf("Expected %s, got %s", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package azure
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// AzureBlobStorage is the Azure Blob Storage struct
+type AzureBlobStorage struct {
+	// Provider is the name of the cloud storage service to use.
+	Provider types.Provider
+	// DefaultBucket is the Azure container to use for storage
+	DefaultBucket string
+	// AccountName is the name of the Azure storage account
+	AccountName string
+	// AccountKey is the shared key of the Azure storage account
+	AccountKey string
+	// SASToken is the shared access signature used instead of the account key
+	SASToken string
+	// ConnectionString is the storage account connection string used instead of the account name and key
+	ConnectionString string
+	// Endpoint is the URL of the blob service of the storage account (e.g. the Azurite emulator).
+	Endpoint string
+	// BaseURL is the URL DefaultBucket is served from, used to build the URL of uploaded files.
+	BaseURL string
+	// DefaultTimeout is the time-to-live for time-dependent Azure operations
+	DefaultTimeout int64
+	// UseAsync enables asynchronous operations with go routines.
+	UseAsync bool
+	// MaxConcurrency is the maximum number of concurrent uploads when UseAsync is enabled.
+	MaxConcurrency int
+	// RetryPolicy configures how failed uploads are retried. Uploads are not retried when it is nil.
+	RetryPolicy *types.RetryPolicy
+	// MultipartThreshold is the size in bytes from which uploads are split into blocks.
+	MultipartThreshold int64
+	// PartSize is the size in bytes of each block of a block upload.
+	PartSize int64
+	// PartConcurrency is the number of blocks of a block upload that are uploaded at once.
+	PartConcurrency int
+	// Azure blob client
+	Client *azblob.Client
+	// PublicRead enables public read access to uploaded files.
+	PublicRead bool
+	// EnableDebug enables debug logging.
+	EnableDebug bool
+}
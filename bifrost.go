@@ -11,14 +11,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 
 	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
 
+	"github.com/opensaucerer/bifrost/azure"
 	"github.com/opensaucerer/bifrost/gcs"
+	"github.com/opensaucerer/bifrost/local"
+	"github.com/opensaucerer/bifrost/memory"
 	"github.com/opensaucerer/bifrost/pinata"
 	bs3 "github.com/opensaucerer/bifrost/s3"
 	bconfig "github.com/opensaucerer/bifrost/shared/config"
@@ -26,11 +34,6 @@ import (
 	"github.com/opensaucerer/bifrost/shared/request"
 	"github.com/opensaucerer/bifrost/wasabi"
 	"google.golang.org/api/option"
-
-	awsv1 "github.com/aws/aws-sdk-go/aws"
-	credv1 "github.com/aws/aws-sdk-go/aws/credentials"
-	sessv1 "github.com/aws/aws-sdk-go/aws/session"
-	s3v1 "github.com/aws/aws-sdk-go/service/s3"
 )
 
 // NewRainbowBridge returns a new Rainbow Bridge for shipping files to your specified cloud storage service.
@@ -68,8 +71,9 @@ func NewRainbowBridge(bc *BridgeConfig) (RainbowBridge, error) {
 		}
 	}
 
-	// verify that the provider is valid
-	if _, ok := providers[bc.Provider]; !ok {
+	// verify that the provider is registered
+	provider, ok := lookup(bc.Provider)
+	if !ok {
 		return nil, &errors.BifrostError{
 			Err:       fmt.Errorf("invalid provider: %s", bc.Provider),
 			ErrorCode: errors.ErrInvalidProvider,
@@ -82,47 +86,48 @@ func NewRainbowBridge(bc *BridgeConfig) (RainbowBridge, error) {
 		// Just log a warning
 		if bc.EnableDebug {
 			// @TODO: create a logger
-			log.Printf(errors.WARN+"WARN: "+errors.NONE+"No bucket specified for provider %s. This might cause errors or require you to specify a bucket for each operation.", providers[bc.Provider])
-		}
-	}
-
-	// Create a new bridge based on the provider
-	switch bc.Provider {
-	case SimpleStorageService:
-		return newSimpleStorageService(bc)
-	case GoogleCloudStorage:
-		return newGoogleCloudStorage(bc)
-	case PinataCloud:
-		return newPinataCloud(bc)
-	case WasabiCloudStorage:
-		return newWasabiCloudStorage(bc)
-	default:
-		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("invalid provider: %s", bc.Provider),
-			ErrorCode: errors.ErrBadRequest,
+			log.Printf(errors.WARN+"WARN: "+errors.NONE+"No bucket specified for provider %s. This might cause errors or require you to specify a bucket for each operation.", displayName(bc.Provider))
 		}
 	}
 
+	// Create a new bridge with the factory the provider registered
+	return provider.factory(bc)
 }
 
 // newPinataCloud returns a new client for Pinata Cloud.
 func newPinataCloud(bc *BridgeConfig) (RainbowBridge, error) {
-	// @TODO: add support for API key and API secret
-	if bc.PinataJWT == "" {
+	var client *request.Client
+	switch {
+	case bc.PinataJWT != "":
+		client = request.NewClient(bconfig.URLPinataAuth, bc.PinataJWT, bc.DefaultTimeout)
+	case bc.PinataAPIKey != "" && bc.PinataSecretAPIKey != "":
+		client = request.NewKeyClient(bconfig.URLPinataAuth, bc.PinataAPIKey, bc.PinataSecretAPIKey, bc.DefaultTimeout)
+	case bc.PinataAPIKey != "" || bc.PinataSecretAPIKey != "":
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("pinata API key and secret must be set together"),
+			ErrorCode: errors.ErrInvalidCredentials,
+		}
+	default:
 		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("pinata JWT is required"),
+			Err:       fmt.Errorf("pinata JWT or API key and secret are required"),
 			ErrorCode: errors.ErrUnauthorized,
 		}
 	}
 
 	var p = pinata.PinataCloud{
-		PinataJWT:      bc.PinataJWT,
-		Provider:       providers[bc.Provider],
-		DefaultTimeout: bc.DefaultTimeout,
-		PublicRead:     bc.PublicRead,
-		UseAsync:       bc.UseAsync,
-		EnableDebug:    bc.EnableDebug,
-		Client:         request.NewClient(bconfig.URLPinataAuth, bc.PinataJWT, bc.DefaultTimeout),
+		PinataJWT:          bc.PinataJWT,
+		PinataAPIKey:       bc.PinataAPIKey,
+		PinataSecretAPIKey: bc.PinataSecretAPIKey,
+		Provider:           providers[bc.Provider],
+		DefaultTimeout:     bc.DefaultTimeout,
+		PublicRead:         bc.PublicRead,
+		UseAsync:           bc.UseAsync,
+		MaxConcurrency:     bc.MaxConcurrency,
+		RetryPolicy:        bc.RetryPolicy,
+		EnableDebug:        bc.EnableDebug,
+		PinPollInterval:    bc.PinPollInterval,
+		VerifyCID:          bc.VerifyCID,
+		Client:             client,
 	}
 	// authenticate with Pinata Cloud
 	if err := p.Preflight(); err != nil {
@@ -167,107 +172,254 @@ func newGoogleCloudStorage(bc *BridgeConfig) (RainbowBridge, error) {
 		EnableDebug:     bc.EnableDebug,
 		PublicRead:      bc.PublicRead,
 		UseAsync:        bc.UseAsync,
+		MaxConcurrency:  bc.MaxConcurrency,
+		RetryPolicy:     bc.RetryPolicy,
 	}, nil
 }
 
 // newSimpleStorageService returns a new client for AWS S3
 func newSimpleStorageService(bc *BridgeConfig) (RainbowBridge, error) {
-	var client *awss3.Client
+	return simpleStorageService(bc, nil)
+}
+
+// simpleStorageService returns a new client for S3 or an S3-compatible service, loading the shared AWS configuration
+// with opts.
+func simpleStorageService(bc *BridgeConfig, opts []func(*awsconfig.LoadOptions) error) (*bs3.SimpleStorageService, error) {
+	region := bc.Region
+	if bc.Endpoint != "" && region == "" {
+		// S3-compatible services mostly ignore the region but requests still have to be signed with one
+		region = bconfig.DefaultS3CompatibleRegion
+	}
+	// point the client at an S3-compatible service when an endpoint is set
+	endpoint := func(o *awss3.Options) {
+		if bc.Endpoint != "" {
+			o.EndpointResolver = awss3.EndpointResolverFromURL(bc.Endpoint)
+		}
+		o.UsePathStyle = bc.ForcePathStyle
+	}
+
+	client, err := newS3Client(bc, region, opts, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &bs3.SimpleStorageService{
+		Provider:           providers[bc.Provider],
+		DefaultBucket:      bc.DefaultBucket,
+		Region:             region,
+		Endpoint:           bc.Endpoint,
+		ForcePathStyle:     bc.ForcePathStyle,
+		BaseURL:            bc.BaseURL,
+		DefaultTimeout:     bc.DefaultTimeout,
+		PublicRead:         bc.PublicRead,
+		SecretKey:          bc.SecretKey,
+		AccessKey:          bc.AccessKey,
+		Client:             client,
+		EnableDebug:        bc.EnableDebug,
+		UseAsync:           bc.UseAsync,
+		MaxConcurrency:     bc.MaxConcurrency,
+		RetryPolicy:        bc.RetryPolicy,
+		MultipartThreshold: bc.MultipartThreshold,
+		PartSize:           bc.PartSize,
+		PartConcurrency:    bc.PartConcurrency,
+	}, nil
+}
+
+// newWasabiCloudStorage returns a new client for Wasabi Cloud Storage
+func newWasabiCloudStorage(bc *BridgeConfig) (RainbowBridge, error) {
+	// Wasabi is an S3-compatible service served from a regional endpoint with path-style addressing
+	wc := *bc
+	wc.Endpoint = fmt.Sprintf(bconfig.URLWasabiEndpoint, bc.Region)
+	wc.ForcePathStyle = true
+	if wc.BaseURL == "" {
+		wc.BaseURL = fmt.Sprintf(bconfig.URLWasabiCloudStorage, bc.DefaultBucket, bc.Region, "")
+	}
+	s, err := simpleStorageService(&wc, wasabiProfile())
+	if err != nil {
+		return nil, err
+	}
+	return &wasabi.WasabiCloudStorage{SimpleStorageService: s}, nil
+}
+
+/*
+wasabiProfile returns the options reading the shared AWS configuration from the wasabi profile.
+
+The profile is only used when it exists and AWS_PROFILE does not name another one, so that Wasabi falls back to the
+default shared configuration like S3 otherwise.
+*/
+func wasabiProfile() []func(*awsconfig.LoadOptions) error {
+	env, err := awsconfig.NewEnvConfig()
+	if err != nil || env.SharedConfigProfile != "" {
+		return nil
+	}
+	_, err = awsconfig.LoadSharedConfigProfile(context.Background(), bconfig.WasabiCloudStorage, func(o *awsconfig.LoadSharedConfigOptions) {
+		if env.SharedConfigFile != "" {
+			o.ConfigFiles = []string{env.SharedConfigFile}
+		}
+		if env.SharedCredentialsFile != "" {
+			o.CredentialsFiles = []string{env.SharedCredentialsFile}
+		}
+	})
+	var missing awsconfig.SharedConfigProfileNotExistError
+	if errors.As(err, &missing) {
+		return nil
+	}
+	return []func(*awsconfig.LoadOptions) error{awsconfig.WithSharedConfigProfile(bconfig.WasabiCloudStorage)}
+}
+
+/*
+newS3Client returns a client for S3 or an S3-compatible service, configured by optFns.
+
+The client authenticates with AccessKey and SecretKey when both are set and falls back to the shared AWS configuration,
+loaded with opts, otherwise.
+*/
+func newS3Client(bc *BridgeConfig, region string, opts []func(*awsconfig.LoadOptions) error, optFns ...func(*awss3.Options)) (*awss3.Client, error) {
 	if bc.AccessKey != "" && bc.SecretKey != "" {
 		creds := credentials.NewStaticCredentialsProvider(bc.AccessKey, bc.SecretKey, "")
-		cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithCredentialsProvider(creds), awsconfig.WithRegion(bc.Region))
-		if err != nil {
-			return nil, &errors.BifrostError{
-				Err:       err,
-				ErrorCode: errors.ErrUnauthorized,
-			}
+		opts = []func(*awsconfig.LoadOptions) error{awsconfig.WithCredentialsProvider(creds)}
+	}
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrUnauthorized,
 		}
-		client = awss3.NewFromConfig(cfg)
-	} else {
-		// Load AWS Shared Configuration
-		cfg, err := awsconfig.LoadDefaultConfig(context.TODO())
-		if err != nil {
-			return nil, &errors.BifrostError{
-				Err:       err,
-				ErrorCode: errors.ErrUnauthorized,
-			}
+	}
+	return awss3.NewFromConfig(cfg, optFns...), nil
+}
+
+// newLocalStorage returns a new local filesystem storage rooted at bc.Root.
+func newLocalStorage(bc *BridgeConfig) (RainbowBridge, error) {
+	if bc.Root == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("root directory is required"),
+			ErrorCode: errors.ErrInvalidConfig,
 		}
-		client = awss3.NewFromConfig(cfg)
 	}
-	return &bs3.SimpleStorageService{
+	root, err := filepath.Abs(bc.Root)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidConfig,
+		}
+	}
+	// create the bucket directory so that the storage is usable right away
+	if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(bc.DefaultBucket)), 0755); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidConfig,
+		}
+	}
+
+	return &local.LocalStorage{
 		Provider:       providers[bc.Provider],
+		Root:           root,
 		DefaultBucket:  bc.DefaultBucket,
-		Region:         bc.Region,
+		BaseURL:        bc.BaseURL,
 		DefaultTimeout: bc.DefaultTimeout,
 		PublicRead:     bc.PublicRead,
-		SecretKey:      bc.SecretKey,
-		AccessKey:      bc.AccessKey,
-		Client:         client,
-		EnableDebug:    bc.EnableDebug,
 		UseAsync:       bc.UseAsync,
+		MaxConcurrency: bc.MaxConcurrency,
+		RetryPolicy:    bc.RetryPolicy,
+		EnableDebug:    bc.EnableDebug,
 	}, nil
 }
 
-// newWasabiCloudStorage returns a new client for Wasabi Cloud Storage
-func newWasabiCloudStorage(bc *BridgeConfig) (RainbowBridge, error) {
+// newMemoryStorage returns a new, empty in-memory storage.
+func newMemoryStorage(bc *BridgeConfig) (RainbowBridge, error) {
+	return &memory.MemoryStorage{
+		Provider:       providers[bc.Provider],
+		DefaultBucket:  bc.DefaultBucket,
+		BaseURL:        bc.BaseURL,
+		DefaultTimeout: bc.DefaultTimeout,
+		PublicRead:     bc.PublicRead,
+		UseAsync:       bc.UseAsync,
+		MaxConcurrency: bc.MaxConcurrency,
+		RetryPolicy:    bc.RetryPolicy,
+		EnableDebug:    bc.EnableDebug,
+	}, nil
+}
 
-	// Wasabi is an S3-compatible service, so we can use the same client
-	var client *s3v1.S3
-	if bc.AccessKey != "" && bc.SecretKey != "" {
+// newAzureBlobStorage returns a new client for Azure Blob Storage.
+// A connection string takes precedence over a SAS token, which takes precedence over the account key.
+func newAzureBlobStorage(bc *BridgeConfig) (RainbowBridge, error) {
+	endpoint := bc.Endpoint
+	if endpoint == "" && bc.AccessKey != "" {
+		endpoint = fmt.Sprintf(bconfig.URLAzureBlobStorage, bc.AccessKey)
+	}
 
-		s3Config := awsv1.Config{
-			Endpoint:         awsv1.String(fmt.Sprintf(bconfig.URLWasabiEndpoint, bc.Region)),
-			Region:           awsv1.String(bc.Region),
-			S3ForcePathStyle: awsv1.Bool(true),
-			Credentials:      credv1.NewStaticCredentials(bc.AccessKey, bc.SecretKey, ""),
+	var client *azblob.Client
+	var err error
+	switch {
+	case bc.ConnectionString != "":
+		client, err = azblob.NewClientFromConnectionString(bc.ConnectionString, nil)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrUnauthorized,
+			}
 		}
-
-		s3session, err := sessv1.NewSessionWithOptions(sessv1.Options{
-			Config: s3Config,
-		})
-
+		// the connection string holds the endpoint along with any SAS token, which must not end up in file URLs
+		if u, err := url.Parse(client.URL()); err == nil {
+			u.RawQuery = ""
+			endpoint = u.String()
+		}
+	case bc.SASToken != "":
+		if endpoint == "" {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("account name or endpoint is required with a SAS token"),
+				ErrorCode: errors.ErrInvalidConfig,
+			}
+		}
+		client, err = azblob.NewClientWithNoCredential(strings.TrimSuffix(endpoint, "/")+"/?"+strings.TrimPrefix(bc.SASToken, "?"), nil)
 		if err != nil {
 			return nil, &errors.BifrostError{
 				Err:       err,
 				ErrorCode: errors.ErrUnauthorized,
 			}
 		}
-
-		client = s3v1.New(s3session)
-
-	} else {
-
-		// load default config
-		s3Config := awsv1.Config{
-			Endpoint:         awsv1.String(fmt.Sprintf(bconfig.URLWasabiEndpoint, bc.Region)),
-			Region:           awsv1.String(bc.Region),
-			S3ForcePathStyle: awsv1.Bool(true),
+	case bc.AccessKey != "" && bc.SecretKey != "":
+		cred, err := azblob.NewSharedKeyCredential(bc.AccessKey, bc.SecretKey)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrInvalidCredentials,
+			}
 		}
-
-		s3session, err := sessv1.NewSessionWithOptions(sessv1.Options{
-			Config:  s3Config,
-			Profile: bconfig.WasabiCloudStorage,
-		})
+		client, err = azblob.NewClientWithSharedKeyCredential(endpoint, cred, nil)
 		if err != nil {
 			return nil, &errors.BifrostError{
 				Err:       err,
 				ErrorCode: errors.ErrUnauthorized,
 			}
 		}
-
-		client = s3v1.New(s3session)
-
+	default:
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("a connection string, a SAS token or an account name and key is required"),
+			ErrorCode: errors.ErrUnauthorized,
+		}
 	}
-	return &wasabi.WasabiCloudStorage{
-		Provider:       providers[bc.Provider],
-		DefaultBucket:  bc.DefaultBucket,
-		Region:         bc.Region,
-		DefaultTimeout: bc.DefaultTimeout,
-		PublicRead:     bc.PublicRead,
-		SecretKey:      bc.SecretKey,
-		AccessKey:      bc.AccessKey,
-		Client:         client,
-		EnableDebug:    bc.EnableDebug,
-		UseAsync:       bc.UseAsync,
+
+	return &azure.AzureBlobStorage{
+		Provider:           providers[bc.Provider],
+		DefaultBucket:      bc.DefaultBucket,
+		AccountName:        bc.AccessKey,
+		AccountKey:         bc.SecretKey,
+		SASToken:           bc.SASToken,
+		ConnectionString:   bc.ConnectionString,
+		Endpoint:           strings.TrimSuffix(endpoint, "/"),
+		BaseURL:            bc.BaseURL,
+		DefaultTimeout:     bc.DefaultTimeout,
+		PublicRead:         bc.PublicRead,
+		Client:             client,
+		EnableDebug:        bc.EnableDebug,
+		UseAsync:           bc.UseAsync,
+		MaxConcurrency:     bc.MaxConcurrency,
+		RetryPolicy:        bc.RetryPolicy,
+		MultipartThreshold: bc.MultipartThreshold,
+		PartSize:           bc.PartSize,
+		PartConcurrency:    bc.PartConcurrency,
 	}, nil
 }
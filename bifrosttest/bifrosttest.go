@@ -0,0 +1,128 @@
+/*
+Package bifrosttest provides an in-memory rainbow bridge for testing code built on bifrost without cloud credentials.
+
+The bridge implements bifrost.RainbowBridge in full, keeps every upload in memory for inspection and can be told to fail
+uploads or slow down to exercise error handling:
+
+	func TestAvatarUpload(t *testing.T) {
+		bridge := bifrosttest.NewBridge(t, nil)
+		bridge.FailUpload(2, syscall.ECONNRESET)
+
+		svc := NewAvatarService(bridge)
+		...
+		bridge.AssertStored(t, "avatars/42.png", png)
+		bridge.AssertOption(t, "avatars/42.png", bifrost.OptACL, bifrost.ACLPublicRead)
+	}
+*/
+package bifrosttest
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/opensaucerer/bifrost"
+	"github.com/opensaucerer/bifrost/memory"
+)
+
+// Bridge is an in-memory rainbow bridge with helpers for inspecting what was stored and injecting faults.
+// See memory.MemoryStorage for FailUpload, FailPing, SetLatency, Objects and Reset.
+type Bridge struct {
+	*memory.MemoryStorage
+}
+
+// Object is a file stored in a Bridge.
+type Object = memory.Object
+
+// NewBridge returns an empty in-memory bridge configured by bc, which may be nil, and disconnects it when the test ends.
+// The provider in bc is always set to bifrost.MemoryStorage.
+func NewBridge(tb testing.TB, bc *bifrost.BridgeConfig) *Bridge {
+	tb.Helper()
+
+	config := bifrost.BridgeConfig{}
+	if bc != nil {
+		config = *bc
+	}
+	config.Provider = bifrost.MemoryStorage
+
+	bridge, err := bifrost.NewRainbowBridge(&config)
+	if err != nil {
+		tb.Fatalf("bifrosttest: failed to create bridge: %v", err)
+	}
+	tb.Cleanup(func() {
+		bridge.Disconnect()
+	})
+	return &Bridge{MemoryStorage: bridge.(*memory.MemoryStorage)}
+}
+
+// Files returns the names of the files stored in the default bucket, sorted.
+func (b *Bridge) Files() []string {
+	objects := b.Objects("")
+	names := make([]string, len(objects))
+	for i, obj := range objects {
+		names[i] = obj.Name
+	}
+	return names
+}
+
+// Contents returns the content of the file stored as name in the default bucket, and false if there is none.
+func (b *Bridge) Contents(name string) ([]byte, bool) {
+	obj, ok := b.Object(name)
+	return obj.Data, ok
+}
+
+// AssertStored fails the test if no file is stored as name in the default bucket or if its content is not want.
+func (b *Bridge) AssertStored(tb testing.TB, name string, want []byte) {
+	tb.Helper()
+	got, ok := b.Contents(name)
+	if !ok {
+		tb.Errorf("bifrosttest: expected %q to be stored, stored files are %q", name, b.Files())
+		return
+	}
+	if !bytes.Equal(got, want) {
+		tb.Errorf("bifrosttest: expected %q to contain %q, got %q", name, want, got)
+	}
+}
+
+// AssertNotStored fails the test if a file is stored as name in the default bucket.
+func (b *Bridge) AssertNotStored(tb testing.TB, name string) {
+	tb.Helper()
+	if _, ok := b.Object(name); ok {
+		tb.Errorf("bifrosttest: expected %q not to be stored", name)
+	}
+}
+
+// AssertOption fails the test if the file stored as name was not uploaded with the option key set to want,
+// global options included.
+func (b *Bridge) AssertOption(tb testing.TB, name, key string, want interface{}) {
+	tb.Helper()
+	obj, ok := b.Object(name)
+	if !ok {
+		tb.Errorf("bifrosttest: expected %q to be stored, stored files are %q", name, b.Files())
+		return
+	}
+	got, ok := obj.Options[key]
+	if !ok {
+		tb.Errorf("bifrosttest: expected %q to be uploaded with option %q", name, key)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		tb.Errorf("bifrosttest: expected option %q of %q to be %v, got %v", key, name, want, got)
+	}
+}
+
+// AssertMetadata fails the test if the file stored as name does not have the metadata key set to want.
+func (b *Bridge) AssertMetadata(tb testing.TB, name, key, want string) {
+	tb.Helper()
+	obj, ok := b.Object(name)
+	if !ok {
+		tb.Errorf("bifrosttest: expected %q to be stored, stored files are %q", name, b.Files())
+		return
+	}
+	if got, ok := obj.Metadata[key]; !ok || got != want {
+		tb.Errorf("bifrosttest: expected metadata %q of %q to be %q, got %q", key, name, want, got)
+	}
+}
+
+// make sure that a Bridge can stand in for any other bridge
+var _ bifrost.RainbowBridge = (*Bridge)(nil)
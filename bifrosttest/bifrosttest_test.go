@@ -0,0 +1,100 @@
+package bifrosttest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/opensaucerer/bifrost"
+	"github.com/opensaucerer/bifrost/bifrosttest"
+)
+
+func TestBridge(t *testing.T) {
+
+	t.Run("Tests inspection helpers", func(t *testing.T) {
+		bridge := bifrosttest.NewBridge(t, &bifrost.BridgeConfig{DefaultBucket: "bifrost", PublicRead: true})
+
+		_, err := bridge.UploadMultiFile(bifrost.MultiFile{
+			Files: []bifrost.File{
+				{
+					Handle:   strings.NewReader("hello"),
+					Filename: "hello.txt",
+					Options: map[string]interface{}{
+						bifrost.OptMetadata: map[string]string{"lang": "en"},
+					},
+				},
+			},
+			GlobalOptions: map[string]interface{}{
+				bifrost.OptContentType: "text/plain",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bridge.AssertStored(t, "hello.txt", []byte("hello"))
+		bridge.AssertNotStored(t, "world.txt")
+		bridge.AssertOption(t, "hello.txt", bifrost.OptContentType, "text/plain")
+		bridge.AssertOption(t, "hello.txt", bifrost.OptACL, bifrost.ACLPublicRead)
+		bridge.AssertMetadata(t, "hello.txt", "lang", "en")
+		if files := bridge.Files(); len(files) != 1 || files[0] != "hello.txt" {
+			t.Errorf("Unexpected files %v", files)
+		}
+	})
+
+	t.Run("Tests FailUpload fails the n-th upload", func(t *testing.T) {
+		bridge := bifrosttest.NewBridge(t, nil)
+		injected := errors.New("injected")
+		bridge.FailUpload(2, injected)
+
+		o, err := bridge.UploadMultiFile(bifrost.MultiFile{
+			Files: []bifrost.File{
+				{Handle: strings.NewReader("1"), Filename: "1.txt"},
+				{Handle: strings.NewReader("2"), Filename: "2.txt"},
+				{Handle: strings.NewReader("3"), Filename: "3.txt"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o[0].Error != nil || !errors.Is(o[1].Error, injected) || o[2].Error != nil {
+			t.Errorf("Expected only the second upload to fail, got %v %v %v", o[0].Error, o[1].Error, o[2].Error)
+		}
+		bridge.AssertNotStored(t, "2.txt")
+	})
+
+	t.Run("Tests FailUpload with a transient error and a retry policy", func(t *testing.T) {
+		bridge := bifrosttest.NewBridge(t, &bifrost.BridgeConfig{
+			RetryPolicy: &bifrost.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		})
+		bridge.FailUpload(1, syscall.ECONNRESET)
+		bridge.FailUpload(2, syscall.ECONNRESET)
+
+		if _, err := bridge.UploadFile(bifrost.File{Handle: strings.NewReader("retried"), Filename: "retried.txt"}); err != nil {
+			t.Fatalf("Expected the upload to succeed on the third attempt: %v", err)
+		}
+		if bridge.Uploads() != 3 {
+			t.Errorf("Expected 3 upload attempts, got %d", bridge.Uploads())
+		}
+		bridge.AssertStored(t, "retried.txt", []byte("retried"))
+	})
+
+	t.Run("Tests SetLatency delays operations", func(t *testing.T) {
+		bridge := bifrosttest.NewBridge(t, nil)
+		bridge.SetLatency(time.Second)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := bridge.UploadFileContext(ctx, bifrost.File{Handle: strings.NewReader("slow"), Filename: "slow.txt"}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the upload to time out, got %v", err)
+		}
+
+		bridge.Reset()
+		if _, err := bridge.UploadFile(bifrost.File{Handle: strings.NewReader("fast"), Filename: "fast.txt"}); err != nil {
+			t.Errorf("Expected Reset to remove the latency: %v", err)
+		}
+	})
+}
@@ -0,0 +1,224 @@
+/*
+Package cid computes the IPFS CID of a file without uploading it, e.g. to dedupe files or to record the CID of a file
+before it is pinned.
+
+Files are imported the way Pinata and IPFS (kubo) import them by default: they are split into chunks of ChunkSize
+bytes which are linked together in a balanced UnixFS DAG of up to MaxLinks links per node, hashed with SHA2-256.
+*/
+package cid
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+)
+
+// NewBuilder returns a Builder computing a CID of the given version, V0 or V1.
+func NewBuilder(version int) (*Builder, error) {
+	if version != V0 && version != V1 {
+		return nil, fmt.Errorf("unsupported CID version: %d", version)
+	}
+	return &Builder{version: version}, nil
+}
+
+// Write adds p to the file. It never returns an error, so a Builder can be used as the writer of an io.TeeReader.
+func (b *Builder) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		m := ChunkSize - len(b.buf)
+		if m > len(p) {
+			m = len(p)
+		}
+		b.buf = append(b.buf, p[:m]...)
+		p = p[m:]
+		if len(b.buf) == ChunkSize {
+			b.leaves = append(b.leaves, b.leaf(b.buf))
+			b.buf = b.buf[:0]
+		}
+	}
+	return n, nil
+}
+
+// Sum returns the CID of the file written so far. It does not change the Builder, so more bytes can be written after it.
+func (b *Builder) Sum() string {
+	level := b.leaves
+	// the chunk being filled is the last one, and an empty file is a single empty chunk
+	if len(b.buf) > 0 || len(level) == 0 {
+		level = append(level[:len(level):len(level)], b.leaf(b.buf))
+	}
+	for len(level) > 1 {
+		parents := make([]node, 0, (len(level)+MaxLinks-1)/MaxLinks)
+		for len(level) > 0 {
+			n := MaxLinks
+			if n > len(level) {
+				n = len(level)
+			}
+			parents = append(parents, b.parent(level[:n]))
+			level = level[n:]
+		}
+		level = parents
+	}
+	return encode(b.version, level[0].cid)
+}
+
+// Reader returns the CID of the bytes read from r until EOF.
+func Reader(r io.Reader, version int) (string, error) {
+	b, err := NewBuilder(version)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(b, r); err != nil {
+		return "", err
+	}
+	return b.Sum(), nil
+}
+
+// File returns the CID of the file at path.
+func File(path string, version int) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return Reader(file, version)
+}
+
+/*
+VersionOf returns the CID version Pinata uses for a file uploaded with options, i.e. the cidVersion of the
+pinataOptions option. It defaults to V0, like Pinata.
+*/
+func VersionOf(options map[string]interface{}) int {
+	opt, _ := options[config.OptPinata].(map[string]interface{})
+	switch v := opt["cidVersion"].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		if version, err := strconv.Atoi(v); err == nil {
+			return version
+		}
+	}
+	return V0
+}
+
+// leaf returns the block of a chunk of the file: a raw block for V1 and a UnixFS file node otherwise.
+func (b *Builder) leaf(chunk []byte) node {
+	if b.version == V1 {
+		return node{cid: blockCID(b.version, codecRaw, chunk), fileSize: uint64(len(chunk)), tsize: uint64(len(chunk))}
+	}
+	var data []byte
+	data = appendVarintField(data, 1, unixfsFile)
+	if len(chunk) > 0 {
+		data = appendBytesField(data, 2, chunk)
+	}
+	data = appendVarintField(data, 3, uint64(len(chunk)))
+	block := appendBytesField(nil, 1, data)
+	return node{cid: blockCID(b.version, codecDagPB, block), fileSize: uint64(len(chunk)), tsize: uint64(len(block))}
+}
+
+// parent returns the UnixFS file node linking to children.
+func (b *Builder) parent(children []node) node {
+	var block, data []byte
+	var fileSize, tsize uint64
+	data = appendVarintField(data, 1, unixfsFile)
+	for _, child := range children {
+		fileSize += child.fileSize
+		tsize += child.tsize
+	}
+	data = appendVarintField(data, 3, fileSize)
+	for _, child := range children {
+		data = appendVarintField(data, 4, child.fileSize)
+	}
+	// links come before the data in the canonical dag-pb encoding, with an empty name as IPFS writes them
+	for _, child := range children {
+		var link []byte
+		link = appendBytesField(link, 1, child.cid)
+		link = appendBytesField(link, 2, nil)
+		link = appendVarintField(link, 3, child.tsize)
+		block = appendBytesField(block, 2, link)
+	}
+	block = appendBytesField(block, 1, data)
+	return node{cid: blockCID(b.version, codecDagPB, block), fileSize: fileSize, tsize: tsize + uint64(len(block))}
+}
+
+// unixfsFile is the UnixFS data type of files.
+const unixfsFile = 2
+
+// blockCID returns the binary CID of a block. A CIDv0 is the bare multihash of the block.
+func blockCID(version int, codec uint64, block []byte) []byte {
+	sum := sha256.Sum256(block)
+	var c []byte
+	if version == V1 {
+		c = appendVarint(c, 1)
+		c = appendVarint(c, codec)
+	}
+	c = appendVarint(c, hashSHA2_256)
+	c = appendVarint(c, uint64(len(sum)))
+	return append(c, sum[:]...)
+}
+
+// encode returns the string form of a binary CID: base58btc for CIDv0 and multibase base32 for CIDv1.
+func encode(version int, c []byte) string {
+	if version == V1 {
+		return "b" + base32Lower.EncodeToString(c)
+	}
+	return base58(c)
+}
+
+// base32Lower is the unpadded lowercase base32 alphabet of multibase "b".
+var base32Lower = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// base58Alphabet is the bitcoin base58 alphabet.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58 returns b encoded with the bitcoin base58 alphabet.
+func base58(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	// leading zero bytes are written as leading ones
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// appendVarint appends v to b as an unsigned varint.
+func appendVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+// appendVarintField appends a protobuf varint field to b.
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	b = appendVarint(b, uint64(field)<<3)
+	return appendVarint(b, v)
+}
+
+// appendBytesField appends a protobuf length-delimited field to b.
+func appendBytesField(b []byte, field int, v []byte) []byte {
+	b = appendVarint(b, uint64(field)<<3|2)
+	b = appendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
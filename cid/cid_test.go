@@ -0,0 +1,109 @@
+package cid_test
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opensaucerer/bifrost/cid"
+	"github.com/opensaucerer/bifrost/shared/config"
+)
+
+func TestCID(t *testing.T) {
+	t.Run("Tests Reader method with single chunk files", func(t *testing.T) {
+		for _, tc := range []struct {
+			data    string
+			version int
+			want    string
+		}{
+			{"", cid.V0, "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH"},
+			{"hello world", cid.V0, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD"},
+			{"hello world\n", cid.V0, "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o"},
+			{"", cid.V1, "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"},
+			{"hello world", cid.V1, "bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e"},
+		} {
+			got, err := cid.Reader(strings.NewReader(tc.data), tc.version)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("Expected CIDv%d %s for %q, got %s", tc.version, tc.want, tc.data, got)
+			}
+		}
+	})
+
+	t.Run("Tests Reader method with chunked files", func(t *testing.T) {
+		// the expected CIDs are those computed by the IPFS importer for the same pseudo-random bytes
+		r := rand.New(rand.NewSource(1))
+		for _, tc := range []struct {
+			size   int
+			v0, v1 string
+		}{
+			{1 << 20, "QmZYS9Y93xKF6USHRyFDbWqbxCcfXiHCTmNqU6CQCktjSg", "bafybeiawubbbiijcxgq6whil6cconrggwc655r4z5zcm6orp65qflmoloy"},
+			{256<<10 + 1, "QmU3irSUue5zJCGXWLCn5tVExpT9F5ZsDTdy61moNSB4w7", "bafybeifegmrh5fqzkirhhtja37fglijymppcrr4am7oedm33ht3cgl62di"},
+			// more chunks than fit under a single node
+			{175 * 256 << 10, "QmZ9ZSPQaoDobbvvsJGDxXs76f13jKrrqyhXptFKxZkbt3", "bafybeihvwxrd2m7mg4uo4cfpnkthgo3d5d5wlydjh7gjfdjnof4y3vhhci"},
+			{175*256<<10 + 7, "QmU6nYSbPUjVZx94Dwso8onuFhF2hGuyy84rgAd6FSngcg", "bafybeigxsjn3e4r5kbkfc5qfscu2sjndqv5edibf35kkwr7u67h54puvua"},
+		} {
+			data := make([]byte, tc.size)
+			r.Read(data)
+			if got, _ := cid.Reader(bytes.NewReader(data), cid.V0); got != tc.v0 {
+				t.Errorf("Expected CIDv0 %s for %d bytes, got %s", tc.v0, tc.size, got)
+			}
+			if got, _ := cid.Reader(bytes.NewReader(data), cid.V1); got != tc.v1 {
+				t.Errorf("Expected CIDv1 %s for %d bytes, got %s", tc.v1, tc.size, got)
+			}
+		}
+	})
+
+	t.Run("Tests Write method after Sum", func(t *testing.T) {
+		// the second write fills the first chunk past the bytes summed so far
+		data := make([]byte, cid.ChunkSize+100)
+		rand.New(rand.NewSource(2)).Read(data)
+		want, _ := cid.Reader(bytes.NewReader(data), cid.V0)
+
+		b, _ := cid.NewBuilder(cid.V0)
+		b.Write(data[:100])
+		if first, _ := cid.Reader(bytes.NewReader(data[:100]), cid.V0); b.Sum() != first {
+			t.Errorf("Expected the CID of the first 100 bytes %s, got %s", first, b.Sum())
+		}
+		b.Write(data[100:])
+		if got := b.Sum(); got != want {
+			t.Errorf("Expected CID %s after writing past Sum, got %s", want, got)
+		}
+	})
+
+	t.Run("Tests File method", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hello.txt")
+		os.WriteFile(path, []byte("hello world"), 0o644)
+
+		got, err := cid.File(path, cid.V0)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD" {
+			t.Errorf("Unexpected CID %s", got)
+		}
+		if _, err := cid.File(filepath.Join(t.TempDir(), "missing.txt"), cid.V0); err == nil {
+			t.Errorf("Expected an error for a missing file")
+		}
+	})
+
+	t.Run("Tests VersionOf method", func(t *testing.T) {
+		if v := cid.VersionOf(nil); v != cid.V0 {
+			t.Errorf("Expected CIDv0 by default, got %d", v)
+		}
+		for _, version := range []interface{}{1, float64(1), "1"} {
+			options := map[string]interface{}{config.OptPinata: map[string]interface{}{"cidVersion": version}}
+			if v := cid.VersionOf(options); v != cid.V1 {
+				t.Errorf("Expected CIDv1 for cidVersion %v, got %d", version, v)
+			}
+		}
+		if _, err := cid.NewBuilder(2); err == nil {
+			t.Errorf("Expected an unsupported version to be rejected")
+		}
+	})
+}
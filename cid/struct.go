@@ -0,0 +1,43 @@
+package cid
+
+// UnixFS import defaults shared by Pinata and IPFS (kubo).
+const (
+	// ChunkSize is the size in bytes of the chunks a file is split into.
+	ChunkSize = 256 << 10
+	// MaxLinks is the maximum number of chunks, or of intermediate nodes, linked from a single node of the file DAG.
+	MaxLinks = 174
+)
+
+// CID versions.
+const (
+	// V0 is the version of base58 CIDs starting with "Qm", the default of Pinata. Chunks are stored as UnixFS nodes.
+	V0 = 0
+	// V1 is the version of base32 CIDs starting with "b". Chunks are stored as raw blocks, as Pinata does for cidVersion 1.
+	V1 = 1
+)
+
+// multicodecs and multihash of the blocks of a file DAG.
+const (
+	codecDagPB   = 0x70
+	codecRaw     = 0x55
+	hashSHA2_256 = 0x12
+)
+
+// Builder computes the CID of a file written to it. The zero value is not usable, use NewBuilder.
+type Builder struct {
+	version int
+	// buf holds the bytes of the chunk being filled.
+	buf []byte
+	// leaves are the chunks of the file written so far.
+	leaves []node
+}
+
+// node is a block of the file DAG as seen from the node linking to it.
+type node struct {
+	// cid is the binary CID of the block.
+	cid []byte
+	// fileSize is the number of bytes of the file under the block.
+	fileSize uint64
+	// tsize is the size in bytes of the block and of every block under it.
+	tsize uint64
+}
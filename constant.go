@@ -13,12 +13,15 @@ It's just a design choice, others might oppose it, that's fine. But keeping the
 */
 
 var (
-	// providers is a map of the supported providers
+	// providers maps the built-in providers to their display names. Providers are registered with RegisterProvider.
 	providers = map[types.Provider]types.Provider{
 		PinataCloud:          "Pinata Cloud Storage",
 		SimpleStorageService: "Simple Storage Service",
 		GoogleCloudStorage:   "Google Cloud Storage",
 		WasabiCloudStorage:   "Wasabi Cloud Storage",
+		LocalStorage:         "Local Storage",
+		MemoryStorage:        "Memory Storage",
+		AzureBlobStorage:     "Azure Blob Storage",
 	}
 )
 
@@ -32,6 +35,12 @@ const (
 	SimpleStorageService types.Provider = "s3"
 	// GoogleCloudStorage is the identifier of the Google Cloud Storage provider
 	GoogleCloudStorage types.Provider = "gcs"
+	// LocalStorage is the identifier of the local filesystem provider
+	LocalStorage types.Provider = "local"
+	// MemoryStorage is the identifier of the in-memory provider, meant for tests
+	MemoryStorage types.Provider = "memory"
+	// AzureBlobStorage is the identifier of the Azure Blob Storage provider
+	AzureBlobStorage types.Provider = "azure"
 
 	// BridgeConfigType is the type of the bridge configuration
 	bridgeConfigType = "BridgeConfig"
@@ -62,6 +71,18 @@ const (
 
 	// ErrClientError is returned when the client returns an error.
 	ErrClientError = "client error"
+
+	// ErrIncompleteMultiFileUpload is returned when a multifile upload fails on some files.
+	ErrIncompleteMultiFileUpload = "incomplete files upload"
+
+	// ErrInvalidParameters is returned when the parameters are invalid.
+	ErrInvalidParameters = "invalid parameters"
+
+	// ErrNotFound is returned when the requested file does not exist with the provider.
+	ErrNotFound = "not found"
+
+	// ErrIntegrity is returned when a file stored with the provider does not match the file that was sent.
+	ErrIntegrity = "integrity check failed"
 )
 
 // Options constants.
@@ -78,4 +99,28 @@ const (
 	OptMetadata = "metadata"
 	// OptPinata is the option to set the pinataOptions
 	OptPinata = "pinataOptions"
+	// OptPinataMetadata is the option to set the pinataMetadata
+	OptPinataMetadata = "pinataMetadata"
+)
+
+// Pin statuses of Pinata pin by CID jobs.
+const (
+	// PinStatusPrechecking is the status of a job that Pinata is checking before searching for the content.
+	PinStatusPrechecking = types.PinStatusPrechecking
+	// PinStatusSearching is the status of a job that Pinata is searching the IPFS network for.
+	PinStatusSearching = types.PinStatusSearching
+	// PinStatusRetrieving is the status of a job whose content Pinata has found and is retrieving.
+	PinStatusRetrieving = types.PinStatusRetrieving
+	// PinStatusPinned is the status of a job whose content is pinned by the account.
+	PinStatusPinned = types.PinStatusPinned
+	// PinStatusExpired is the status of a job whose content could not be found within Pinata's search window.
+	PinStatusExpired = types.PinStatusExpired
+	// PinStatusOverFreeLimit is the status of a job that would take the account over the limits of the free plan.
+	PinStatusOverFreeLimit = types.PinStatusOverFreeLimit
+	// PinStatusOverMaxSize is the status of a job whose content is larger than the account allows.
+	PinStatusOverMaxSize = types.PinStatusOverMaxSize
+	// PinStatusInvalidObject is the status of a job whose CID does not point to a valid object.
+	PinStatusInvalidObject = types.PinStatusInvalidObject
+	// PinStatusBadHostNode is the status of a job whose host nodes could not be reached.
+	PinStatusBadHostNode = types.PinStatusBadHostNode
 )
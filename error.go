@@ -0,0 +1,8 @@
+package bifrost
+
+import "github.com/opensaucerer/bifrost/shared/errors"
+
+// IsNotFound returns true if err is a Bifrost error with the code ErrNotFound, as returned when a file does not exist with the provider.
+func IsNotFound(err error) bool {
+	return errors.IsNotFound(err)
+}
@@ -0,0 +1,641 @@
+/*
+Package failover provides a rainbow bridge that falls over to secondary bridges when the primary bridge fails.
+
+Uploads go to the first healthy bridge, in order. When an upload fails with a retryable error, such as a reset connection
+or a 503 from the provider, the next healthy bridge is tried, and the UploadedFile reports the Provider that stored the
+file. Errors that are not retryable, such as invalid parameters, are returned straight away.
+
+Every bridge has its health tracked: after FailureThreshold consecutive retryable failures, a bridge is skipped for
+CoolDown. Once the cool-down is over, the bridge is probed with IsConnected and, for bridges implementing bifrost.Pinger,
+a Ping, and only goes back into rotation if the probe succeeds.
+
+	bridge, err := failover.NewBridge([]bifrost.RainbowBridge{gcsBridge, s3Bridge}, failover.Options{
+		FailureThreshold: 3,
+		CoolDown:         time.Minute,
+	})
+*/
+package failover
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/opensaucerer/bifrost"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/pool"
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/stream"
+	"github.com/opensaucerer/bifrost/shared/types"
+	"github.com/opensaucerer/bifrost/shared/walk"
+)
+
+// NewBridge returns a rainbow bridge over bridges, the first of which is the primary bridge, in order of preference.
+func NewBridge(bridges []bifrost.RainbowBridge, opts Options) (*Bridge, error) {
+	if len(bridges) == 0 {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("at least one bridge is required"),
+			ErrorCode: errors.ErrInvalidConfig,
+		}
+	}
+	if opts.FailureThreshold < 0 || opts.CoolDown < 0 {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("failure threshold and cool-down must not be negative"),
+			ErrorCode: errors.ErrInvalidConfig,
+		}
+	}
+	if opts.FailureThreshold == 0 {
+		opts.FailureThreshold = DefaultFailureThreshold
+	}
+	if opts.CoolDown == 0 {
+		opts.CoolDown = DefaultCoolDown
+	}
+
+	b := &Bridge{
+		options:   opts,
+		bridges:   append([]bifrost.RainbowBridge(nil), bridges...),
+		providers: make([]types.Provider, len(bridges)),
+		health:    make([]state, len(bridges)),
+	}
+	for i, bridge := range bridges {
+		if bridge == nil {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("bridge %d is nil", i),
+				ErrorCode: errors.ErrInvalidConfig,
+			}
+		}
+		b.providers[i] = bridge.Config().Provider
+	}
+	return b, nil
+}
+
+// Bridges returns the bridges of the failover bridge, in order of preference.
+func (b *Bridge) Bridges() []bifrost.RainbowBridge {
+	return append([]bifrost.RainbowBridge(nil), b.bridges...)
+}
+
+// Health returns the current health of every bridge, in order.
+func (b *Bridge) Health() []Health {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	health := make([]Health, len(b.bridges))
+	for i, s := range b.health {
+		health[i] = Health{
+			Provider:            b.providers[i],
+			Healthy:             !b.unhealthy(s),
+			ConsecutiveFailures: s.failures,
+			LastError:           s.err,
+			CoolDownUntil:       s.until,
+		}
+	}
+	return health
+}
+
+/*
+Probe checks every bridge with IsConnected and Ping, updates their health with the outcome and returns the health of
+every bridge. A bridge that fails the probe is marked unhealthy straight away.
+*/
+func (b *Bridge) Probe(ctx context.Context) []Health {
+	for i := range b.bridges {
+		if err := b.probe(ctx, i); err != nil {
+			b.markDown(i, err)
+		} else {
+			b.succeed(i)
+		}
+	}
+	return b.Health()
+}
+
+/*
+UploadFile uploads a file to the first healthy bridge, falling over to the next ones on retryable errors, and returns
+an error if one occurs. The Provider of the returned UploadedFile is the provider of the bridge that stored the file.
+*/
+func (b *Bridge) UploadFile(fileFace interface{}) (*types.UploadedFile, error) {
+	return b.UploadFileContext(context.Background(), fileFace)
+}
+
+// UploadFileContext is like UploadFile but uses ctx for cancellation and deadlines.
+func (b *Bridge) UploadFileContext(ctx context.Context, fileFace interface{}) (*types.UploadedFile, error) {
+	bFile, err := types.AsFile(fileFace)
+	if err != nil {
+		return nil, err
+	}
+	return b.Upload(ctx, bFile)
+}
+
+/*
+Upload uploads a file to the first healthy bridge, falling over to the next ones on retryable errors, and returns an
+error if one occurs. It is the typed equivalent of UploadFileContext.
+
+When every bridge is unhealthy, they are all tried in order as a last resort. If every attempt fails, the error wraps a
+*FailoverError holding the error of every bridge tried.
+*/
+func (b *Bridge) Upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	// validate struct
+	if err := bFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	// a Handle is read once into a section so that it can be uploaded again to the next bridge
+	var section *io.SectionReader
+	if bFile.Handle != nil {
+		s, cleanup, err := stream.Section(bFile.Handle)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+		defer cleanup()
+		section = s
+	}
+
+	available, cooling := b.candidates(ctx)
+	if len(available) == 0 {
+		available = cooling
+	}
+
+	attempts := make([]Attempt, 0, len(available))
+	for _, i := range available {
+		file := bFile.Clone()
+		if section != nil {
+			file.Handle = io.NewSectionReader(section, 0, section.Size())
+		}
+
+		uploadedFile, err := b.bridges[i].Upload(ctx, file)
+		if err == nil {
+			b.succeed(i)
+			uploadedFile.Provider = b.providers[i]
+			return uploadedFile, nil
+		}
+		if b.options.EnableDebug {
+			log.Printf("Upload of %s to %s failed with err: %s\n", bFile.Filename, b.providers[i], err.Error())
+		}
+		if !b.retryable(i, err) {
+			return nil, err
+		}
+		b.fail(i, err)
+		attempts = append(attempts, Attempt{Provider: b.providers[i], Err: err})
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, &errors.BifrostError{
+		Err:       &FailoverError{Attempts: attempts},
+		ErrorCode: errors.ErrFileOperationFailed,
+	}
+}
+
+// UploadMultiFile uploads multiple files, each to the first healthy bridge.
+func (b *Bridge) UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error) {
+	return b.UploadMultiFileContext(context.Background(), multiFace)
+}
+
+// UploadMultiFileContext is like UploadMultiFile but uses ctx for cancellation and deadlines of every upload.
+func (b *Bridge) UploadMultiFileContext(ctx context.Context, multiFace interface{}) ([]*types.UploadedFile, error) {
+	multiFile, err := types.AsMultiFile(multiFace)
+	if err != nil {
+		return nil, err
+	}
+	return b.UploadMulti(ctx, multiFile)
+}
+
+// UploadMulti uploads multiple files, each to the first healthy bridge, and returns an error if one occurs. It is the
+// typed equivalent of UploadMultiFileContext. A file that no bridge stored has the error in its Error field.
+func (b *Bridge) UploadMulti(ctx context.Context, multiFile types.MultiFile) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := multiFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	uploadedFiles := make([]*types.UploadedFile, len(multiFile.Files))
+	batch := progress.NewBatch(multiFile)
+
+	// upload concurrently when UseAsync is true, writing each result at its file's index to keep the order of multiFile.Files
+	pool.Run(len(multiFile.Files), pool.Size(b.options.UseAsync, b.options.MaxConcurrency), func(i int) {
+		// merge global options into a copy of the file options so that shared maps are never written to
+		file := batch.Track(i, multiFile.Merge(i))
+
+		uploadedFile, err := b.Upload(ctx, file)
+		batch.Done(i, err)
+		if err != nil {
+			uploadedFiles[i] = &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path}
+			return
+		}
+		uploadedFiles[i] = uploadedFile
+	})
+
+	return uploadedFiles, nil
+}
+
+// Config returns the configuration of the primary bridge with Provider set to failover.Provider.
+func (b *Bridge) Config() *types.BridgeConfig {
+	config := *b.bridges[0].Config()
+	config.Provider = Provider
+	config.UseAsync = b.options.UseAsync
+	config.MaxConcurrency = b.options.MaxConcurrency
+	return &config
+}
+
+// Disconnect disconnects every bridge and returns the first error that occurs.
+func (b *Bridge) Disconnect() error {
+	var first error
+	for _, bridge := range b.bridges {
+		if err := bridge.Disconnect(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// IsConnected returns true if any bridge is connected.
+func (b *Bridge) IsConnected() bool {
+	for _, bridge := range b.bridges {
+		if bridge.IsConnected() {
+			return true
+		}
+	}
+	return false
+}
+
+// Ping probes every bridge like Probe and returns an error if none of them is healthy.
+func (b *Bridge) Ping(ctx context.Context) error {
+	for _, health := range b.Probe(ctx) {
+		if health.Healthy {
+			return nil
+		}
+	}
+	return &errors.BifrostError{
+		Err:       fmt.Errorf("no healthy bridge"),
+		ErrorCode: errors.ErrClientError,
+	}
+}
+
+// UploadFolder uploads every file in a local folder, each to the first healthy bridge. Like UploadMultiFile, one
+// UploadedFile is returned per file.
+func (b *Bridge) UploadFolder(foldFace interface{}) ([]*types.UploadedFile, error) {
+	return b.UploadFolderContext(context.Background(), foldFace)
+}
+
+// UploadFolderContext is like UploadFolder but uses ctx for cancellation and deadlines of every upload.
+func (b *Bridge) UploadFolderContext(ctx context.Context, foldFace interface{}) ([]*types.UploadedFile, error) {
+	folder, err := types.AsFolder(foldFace)
+	if err != nil {
+		return nil, err
+	}
+	return b.UploadDir(ctx, folder)
+}
+
+// UploadDir uploads every file in a local folder, each to the first healthy bridge. It is the typed equivalent of UploadFolderContext.
+func (b *Bridge) UploadDir(ctx context.Context, folder types.Folder) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := folder.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	files, err := walk.Files(folder)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if len(files) == 0 {
+		return []*types.UploadedFile{}, nil
+	}
+
+	return b.UploadMulti(ctx, types.MultiFile{
+		Files:         files,
+		GlobalOptions: folder.Options,
+	})
+}
+
+/*
+DeleteFile deletes a file from every bridge that has it, as uploads that failed over may have stored it on any of them,
+and returns the first error that occurs. If no bridge has the file, an error with the code ErrNotFound is returned.
+*/
+func (b *Bridge) DeleteFile(fileFace interface{}) error {
+	return b.DeleteFileContext(context.Background(), fileFace)
+}
+
+// DeleteFileContext is like DeleteFile but uses ctx for cancellation and deadlines.
+func (b *Bridge) DeleteFileContext(ctx context.Context, fileFace interface{}) error {
+	dFile, err := types.AsDeleteFile(fileFace)
+	if err != nil {
+		return err
+	}
+	return b.Delete(ctx, dFile)
+}
+
+// Delete deletes a file from every bridge that has it and returns the first error that occurs. It is the typed
+// equivalent of DeleteFileContext.
+func (b *Bridge) Delete(ctx context.Context, dFile types.DeleteFile) error {
+	// validate struct
+	if err := dFile.Validate(); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	var notFound, failed error
+	missing := 0
+	for i, bridge := range b.bridges {
+		err := bridge.Delete(ctx, dFile)
+		switch {
+		case err == nil:
+			b.succeed(i)
+		case errors.IsNotFound(err):
+			missing++
+			if notFound == nil {
+				notFound = err
+			}
+		default:
+			if b.options.EnableDebug {
+				log.Printf("Delete from %s failed with err: %s\n", b.providers[i], err.Error())
+			}
+			if b.retryable(i, err) {
+				b.fail(i, err)
+			}
+			if failed == nil {
+				failed = err
+			}
+		}
+	}
+	if failed != nil {
+		return failed
+	}
+	if missing == len(b.bridges) {
+		return notFound
+	}
+	return nil
+}
+
+/*
+OpenReader opens a file for reading from the first healthy bridge that has it and returns its contents along with its
+attributes. If no bridge has the file, an error with the code ErrNotFound is returned. The caller must close the
+returned reader.
+*/
+func (b *Bridge) OpenReader(name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	return b.OpenReaderContext(context.Background(), name)
+}
+
+// OpenReaderContext is like OpenReader but uses ctx for cancellation and deadlines.
+func (b *Bridge) OpenReaderContext(ctx context.Context, name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	var rc io.ReadCloser
+	var info *types.ObjectInfo
+	err := b.read(ctx, func(bridge bifrost.RainbowBridge) error {
+		var err error
+		rc, info, err = bridge.OpenReaderContext(ctx, name)
+		return err
+	})
+	return rc, info, err
+}
+
+/*
+DownloadFile downloads a file from the first healthy bridge that has it to localPath and returns its attributes.
+If no bridge has the file, an error with the code ErrNotFound is returned.
+*/
+func (b *Bridge) DownloadFile(name, localPath string) (*types.ObjectInfo, error) {
+	return b.DownloadFileContext(context.Background(), name, localPath)
+}
+
+// DownloadFileContext is like DownloadFile but uses ctx for cancellation and deadlines.
+func (b *Bridge) DownloadFileContext(ctx context.Context, name, localPath string) (*types.ObjectInfo, error) {
+	var info *types.ObjectInfo
+	err := b.read(ctx, func(bridge bifrost.RainbowBridge) error {
+		var err error
+		info, err = bridge.DownloadFileContext(ctx, name, localPath)
+		return err
+	})
+	return info, err
+}
+
+// ListFiles lists the files of the first healthy bridge that can list them, one page at a time.
+func (b *Bridge) ListFiles(opts types.ListOptions) (*types.ListResult, error) {
+	return b.ListFilesContext(context.Background(), opts)
+}
+
+// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines.
+func (b *Bridge) ListFilesContext(ctx context.Context, opts types.ListOptions) (*types.ListResult, error) {
+	var result *types.ListResult
+	err := b.read(ctx, func(bridge bifrost.RainbowBridge) error {
+		var err error
+		result, err = bridge.ListFilesContext(ctx, opts)
+		return err
+	})
+	return result, err
+}
+
+/*
+StatFile returns the attributes of a file from the first healthy bridge that has it.
+If no bridge has the file, an error with the code ErrNotFound is returned.
+*/
+func (b *Bridge) StatFile(name string) (*types.ObjectInfo, error) {
+	return b.StatFileContext(context.Background(), name)
+}
+
+// StatFileContext is like StatFile but uses ctx for cancellation and deadlines.
+func (b *Bridge) StatFileContext(ctx context.Context, name string) (*types.ObjectInfo, error) {
+	var info *types.ObjectInfo
+	err := b.read(ctx, func(bridge bifrost.RainbowBridge) error {
+		var err error
+		info, err = bridge.StatFileContext(ctx, name)
+		return err
+	})
+	return info, err
+}
+
+// Exists returns true if a file exists on any bridge and returns an error if it is on none and a lookup failed.
+func (b *Bridge) Exists(name string) (bool, error) {
+	return b.ExistsContext(context.Background(), name)
+}
+
+// ExistsContext is like Exists but uses ctx for cancellation and deadlines.
+func (b *Bridge) ExistsContext(ctx context.Context, name string) (bool, error) {
+	if _, err := b.StatFileContext(ctx, name); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+/*
+read calls fn with the healthy bridges in order, then with the unhealthy ones, until it succeeds.
+When every bridge fails, the first error that is not ErrNotFound is returned, or the ErrNotFound of the first bridge.
+*/
+func (b *Bridge) read(ctx context.Context, fn func(bridge bifrost.RainbowBridge) error) error {
+	available, cooling := b.candidates(ctx)
+
+	var notFound, failed error
+	for _, i := range append(available, cooling...) {
+		err := fn(b.bridges[i])
+		if err == nil {
+			b.succeed(i)
+			return nil
+		}
+		if b.options.EnableDebug {
+			log.Printf("Read from %s failed with err: %s\n", b.providers[i], err.Error())
+		}
+		if errors.IsNotFound(err) {
+			if notFound == nil {
+				notFound = err
+			}
+			continue
+		}
+		if b.retryable(i, err) {
+			b.fail(i, err)
+		}
+		if failed == nil {
+			failed = err
+		}
+	}
+	if failed != nil {
+		return failed
+	}
+	return notFound
+}
+
+/*
+candidates returns the bridges to try, in order: available holds the healthy bridges and the bridges that passed the
+probe at the end of their cool-down, and cooling holds the bridges that are still unhealthy.
+*/
+func (b *Bridge) candidates(ctx context.Context) (available, cooling []int) {
+	for i := range b.bridges {
+		b.mu.Lock()
+		s := b.health[i]
+		now := time.Now()
+		unhealthy := b.unhealthy(s)
+		probe := unhealthy && !now.Before(s.until)
+		if probe {
+			// claim the probe so that concurrent calls keep skipping the bridge meanwhile
+			b.health[i].until = now.Add(b.options.CoolDown)
+			unhealthy = false
+		}
+		b.mu.Unlock()
+
+		switch {
+		case unhealthy:
+			cooling = append(cooling, i)
+		case probe:
+			if err := b.probe(ctx, i); err != nil {
+				if b.options.EnableDebug {
+					log.Printf("Probe of %s failed with err: %s\n", b.providers[i], err.Error())
+				}
+				b.markDown(i, err)
+				cooling = append(cooling, i)
+				continue
+			}
+			b.succeed(i)
+			available = append(available, i)
+		case !b.bridges[i].IsConnected():
+			cooling = append(cooling, i)
+		default:
+			available = append(available, i)
+		}
+	}
+	return available, cooling
+}
+
+// probe checks that the i-th bridge is connected and, if it implements bifrost.Pinger, that its provider is reachable.
+func (b *Bridge) probe(ctx context.Context, i int) error {
+	bridge := b.bridges[i]
+	if !bridge.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("%s bridge is not connected", b.providers[i]),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	if pinger, ok := bridge.(bifrost.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// retryable returns true if err is worth trying on another bridge, using the IsRetryable classifier of the i-th bridge
+// if it has one.
+func (b *Bridge) retryable(i int, err error) bool {
+	if classifier, ok := b.bridges[i].(interface{ IsRetryable(err error) bool }); ok {
+		return classifier.IsRetryable(err)
+	}
+	return retry.IsTransient(err)
+}
+
+// unhealthy returns true if s has reached the failure threshold. The bridge is skipped until s.until and then probed.
+func (b *Bridge) unhealthy(s state) bool {
+	return s.failures >= b.options.FailureThreshold
+}
+
+// succeed resets the health of the i-th bridge.
+func (b *Bridge) succeed(i int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.health[i] = state{}
+}
+
+// fail records a failure of the i-th bridge, starting its cool-down when the failure threshold is reached.
+func (b *Bridge) fail(i int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := &b.health[i]
+	s.failures++
+	s.err = err
+	if s.failures >= b.options.FailureThreshold {
+		s.until = time.Now().Add(b.options.CoolDown)
+	}
+}
+
+// markDown marks the i-th bridge unhealthy for another cool-down after a failed probe.
+func (b *Bridge) markDown(i int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := &b.health[i]
+	if s.failures < b.options.FailureThreshold {
+		s.failures = b.options.FailureThreshold
+	}
+	s.err = err
+	s.until = time.Now().Add(b.options.CoolDown)
+}
+
+// Attempt is the outcome of an upload attempt on one of the bridges of a failover bridge.
+type Attempt struct {
+	// Provider is the provider of the bridge.
+	Provider types.Provider
+	// Err is the error returned by the bridge.
+	Err error
+}
+
+// FailoverError is the error wrapped by the error returned when an upload fails on every bridge it was tried on.
+type FailoverError struct {
+	// Attempts is the outcome on every bridge tried, in order.
+	Attempts []Attempt
+}
+
+// Error returns the error message, listing the error of every bridge tried.
+func (e *FailoverError) Error() string {
+	if len(e.Attempts) == 0 {
+		return "no bridge available"
+	}
+	failures := make([]string, 0, len(e.Attempts))
+	for _, attempt := range e.Attempts {
+		failures = append(failures, fmt.Sprintf("%s: %s", attempt.Provider, attempt.Err.Error()))
+	}
+	return fmt.Sprintf("upload failed on every bridge: %s", strings.Join(failures, "; "))
+}
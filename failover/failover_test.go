@@ -0,0 +1,229 @@
+package failover_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/opensaucerer/bifrost"
+	"github.com/opensaucerer/bifrost/bifrosttest"
+	"github.com/opensaucerer/bifrost/failover"
+)
+
+// newFailover returns a failover bridge over n in-memory bridges.
+func newFailover(t *testing.T, n int, opts failover.Options) (*failover.Bridge, []*bifrosttest.Bridge) {
+	backends := make([]*bifrosttest.Bridge, n)
+	bridges := make([]bifrost.RainbowBridge, n)
+	for i := range backends {
+		backends[i] = bifrosttest.NewBridge(t, &bifrost.BridgeConfig{DefaultBucket: "bucket"})
+		bridges[i] = backends[i]
+	}
+	bridge, err := failover.NewBridge(bridges, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return bridge, backends
+}
+
+// upload uploads hello.txt through bridge.
+func upload(bridge *failover.Bridge) (*bifrost.UploadedFile, error) {
+	return bridge.Upload(context.Background(), bifrost.File{
+		Handle:   io.MultiReader(strings.NewReader("hello world")),
+		Filename: "hello.txt",
+	})
+}
+
+func TestFailover(t *testing.T) {
+	t.Run("Tests Upload method uses the primary bridge", func(t *testing.T) {
+		bridge, backends := newFailover(t, 2, failover.Options{})
+
+		o, err := upload(bridge)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		backends[0].AssertStored(t, "hello.txt", []byte("hello world"))
+		backends[1].AssertNotStored(t, "hello.txt")
+		if provider := backends[0].Config().Provider; o.Provider != provider {
+			t.Errorf("Expected provider %s, got %s", provider, o.Provider)
+		}
+	})
+
+	t.Run("Tests Upload method falls over on a retryable error", func(t *testing.T) {
+		bridge, backends := newFailover(t, 2, failover.Options{})
+		backends[0].FailUpload(1, syscall.ECONNRESET)
+
+		// a reader of unknown length is uploaded again to the secondary bridge
+		if _, err := upload(bridge); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		backends[0].AssertNotStored(t, "hello.txt")
+		backends[1].AssertStored(t, "hello.txt", []byte("hello world"))
+
+		health := bridge.Health()
+		if !health[0].Healthy || health[0].ConsecutiveFailures != 1 || !errors.Is(health[0].LastError, syscall.ECONNRESET) {
+			t.Errorf("Unexpected health %+v", health[0])
+		}
+	})
+
+	t.Run("Tests Upload method does not fall over on other errors", func(t *testing.T) {
+		bridge, backends := newFailover(t, 2, failover.Options{})
+		backends[0].FailUpload(1, errors.New("access denied"))
+
+		if _, err := upload(bridge); err == nil {
+			t.Fatalf("Expected the upload to fail")
+		}
+		backends[1].AssertNotStored(t, "hello.txt")
+		if health := bridge.Health(); health[0].ConsecutiveFailures != 0 {
+			t.Errorf("Expected the failure not to count against the bridge, got %+v", health[0])
+		}
+	})
+
+	t.Run("Tests Upload method skips an unhealthy bridge", func(t *testing.T) {
+		bridge, backends := newFailover(t, 2, failover.Options{FailureThreshold: 2, CoolDown: time.Hour})
+		backends[0].FailUpload(1, syscall.ECONNRESET)
+		backends[0].FailUpload(2, syscall.ECONNRESET)
+
+		for i := 0; i < 3; i++ {
+			if _, err := upload(bridge); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		// the third upload does not try the primary bridge
+		if uploads := backends[0].Uploads(); uploads != 2 {
+			t.Errorf("Expected 2 uploads to the primary bridge, got %d", uploads)
+		}
+		health := bridge.Health()
+		if health[0].Healthy || health[0].ConsecutiveFailures != 2 || health[0].CoolDownUntil.IsZero() {
+			t.Errorf("Unexpected health %+v", health[0])
+		}
+		if !health[1].Healthy {
+			t.Errorf("Expected the secondary bridge to be healthy")
+		}
+	})
+
+	t.Run("Tests Upload method probes a bridge after its cool-down", func(t *testing.T) {
+		bridge, backends := newFailover(t, 2, failover.Options{FailureThreshold: 1, CoolDown: 10 * time.Millisecond})
+		backends[0].FailUpload(1, syscall.ECONNRESET)
+		if _, err := upload(bridge); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// a failed probe keeps the bridge out of rotation
+		backends[0].FailPing(syscall.ECONNREFUSED)
+		time.Sleep(20 * time.Millisecond)
+		if o, err := upload(bridge); err != nil || backends[0].Uploads() != 1 {
+			t.Fatalf("Expected the upload to skip the primary bridge, got %+v: %v", o, err)
+		}
+		if health := bridge.Health(); health[0].Healthy {
+			t.Errorf("Expected the primary bridge to be unhealthy")
+		}
+
+		backends[0].FailPing(nil)
+		time.Sleep(20 * time.Millisecond)
+		if _, err := upload(bridge); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if uploads := backends[0].Uploads(); uploads != 2 {
+			t.Errorf("Expected the primary bridge to be back in rotation, got %d uploads", uploads)
+		}
+		if health := bridge.Health(); !health[0].Healthy || health[0].ConsecutiveFailures != 0 {
+			t.Errorf("Unexpected health %+v", health[0])
+		}
+	})
+
+	t.Run("Tests Upload method when every bridge fails", func(t *testing.T) {
+		bridge, backends := newFailover(t, 2, failover.Options{})
+		backends[0].FailUpload(1, syscall.ECONNRESET)
+		backends[1].FailUpload(1, syscall.ECONNRESET)
+
+		_, err := upload(bridge)
+		var fe *failover.FailoverError
+		if !errors.As(err, &fe) {
+			t.Fatalf("Expected a failover error, got: %v", err)
+		}
+		if len(fe.Attempts) != 2 {
+			t.Errorf("Unexpected failover error %+v", fe)
+		}
+	})
+
+	t.Run("Tests Probe method", func(t *testing.T) {
+		bridge, backends := newFailover(t, 2, failover.Options{})
+		backends[1].FailPing(syscall.ECONNREFUSED)
+
+		health := bridge.Probe(context.Background())
+		if !health[0].Healthy || health[1].Healthy || health[1].LastError == nil {
+			t.Errorf("Unexpected health %+v", health)
+		}
+		if err := bridge.Ping(context.Background()); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		backends[0].Disconnect()
+		if err := bridge.Ping(context.Background()); err == nil {
+			t.Errorf("Expected Ping to fail without a healthy bridge")
+		}
+	})
+
+	t.Run("Tests DeleteFile method deletes from every bridge", func(t *testing.T) {
+		bridge, backends := newFailover(t, 2, failover.Options{})
+		for _, backend := range backends {
+			if _, err := backend.Upload(context.Background(), bifrost.File{
+				Handle:   strings.NewReader("hello world"),
+				Filename: "hello.txt",
+			}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		if err := bridge.DeleteFile(bifrost.DeleteFile{Filename: "hello.txt"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, backend := range backends {
+			backend.AssertNotStored(t, "hello.txt")
+		}
+
+		if err := bridge.DeleteFile(bifrost.DeleteFile{Filename: "hello.txt"}); !bifrost.IsNotFound(err) {
+			t.Errorf("Expected %s error, got: %v", bifrost.ErrNotFound, err)
+		}
+	})
+
+	t.Run("Tests StatFile and OpenReader methods fall back to the next bridge", func(t *testing.T) {
+		bridge, backends := newFailover(t, 2, failover.Options{})
+		if _, err := backends[1].Upload(context.Background(), bifrost.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		info, err := bridge.StatFile("hello.txt")
+		if err != nil || info.Size != 11 {
+			t.Fatalf("Unexpected stat %+v: %v", info, err)
+		}
+		rc, _, err := bridge.OpenReader("hello.txt")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer rc.Close()
+		if b, _ := io.ReadAll(rc); string(b) != "hello world" {
+			t.Errorf("Expected hello world, got %q", b)
+		}
+
+		if exists, err := bridge.Exists("missing.txt"); err != nil || exists {
+			t.Errorf("Expected missing.txt not to exist, got %v: %v", exists, err)
+		}
+	})
+
+	t.Run("Tests NewBridge method with invalid options", func(t *testing.T) {
+		if _, err := failover.NewBridge(nil, failover.Options{}); err == nil {
+			t.Errorf("Expected a bridge without backends to be rejected")
+		}
+		if _, err := failover.NewBridge([]bifrost.RainbowBridge{bifrosttest.NewBridge(t, nil)}, failover.Options{CoolDown: -time.Second}); err == nil {
+			t.Errorf("Expected a negative cool-down to be rejected")
+		}
+	})
+}
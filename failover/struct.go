@@ -0,0 +1,68 @@
+package failover
+
+import (
+	"sync"
+	"time"
+
+	"github.com/opensaucerer/bifrost"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// Provider is the provider reported by the Config of a failover bridge.
+const Provider types.Provider = "failover"
+
+// Option defaults.
+const (
+	// DefaultFailureThreshold is the number of consecutive failures after which a bridge is taken out of rotation.
+	DefaultFailureThreshold = 3
+	// DefaultCoolDown is how long an unhealthy bridge stays out of rotation before it is probed again.
+	DefaultCoolDown = 30 * time.Second
+)
+
+// Options configures a failover bridge.
+type Options struct {
+	// FailureThreshold is the number of consecutive retryable failures after which a bridge is marked unhealthy and
+	// skipped. It defaults to DefaultFailureThreshold.
+	FailureThreshold int
+	// CoolDown is how long an unhealthy bridge is skipped before it is probed again. A bridge that passes the probe is
+	// healthy again while a bridge that fails it stays unhealthy for another CoolDown. It defaults to DefaultCoolDown.
+	CoolDown time.Duration
+	// UseAsync uploads the files of UploadMultiFile concurrently.
+	UseAsync bool
+	// MaxConcurrency is the maximum number of concurrent uploads when UseAsync is enabled.
+	MaxConcurrency int
+	// EnableDebug logs the failures of every bridge.
+	EnableDebug bool
+}
+
+// Health is the health of one of the bridges of a failover bridge.
+type Health struct {
+	// Provider is the provider of the bridge.
+	Provider types.Provider
+	// Healthy is false while the bridge is cooling down after too many consecutive failures.
+	Healthy bool
+	// ConsecutiveFailures is the number of retryable failures since the last success.
+	ConsecutiveFailures int
+	// LastError is the error of the last failure, if any.
+	LastError error
+	// CoolDownUntil is when an unhealthy bridge is next probed.
+	CoolDownUntil time.Time
+}
+
+// Bridge is a rainbow bridge that uploads to the first healthy bridge, in order, and falls over to the next bridge when
+// an upload fails with a retryable error.
+type Bridge struct {
+	options   Options
+	bridges   []bifrost.RainbowBridge
+	providers []types.Provider
+
+	mu     sync.Mutex
+	health []state
+}
+
+// state is the health tracked for a bridge. It is guarded by Bridge.mu.
+type state struct {
+	failures int
+	err      error
+	until    time.Time
+}
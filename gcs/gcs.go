@@ -13,7 +13,13 @@ import (
 	"cloud.google.com/go/storage"
 	"github.com/opensaucerer/bifrost/shared/config"
 	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/pool"
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/stream"
 	"github.com/opensaucerer/bifrost/shared/types"
+	"github.com/opensaucerer/bifrost/shared/walk"
+	"google.golang.org/api/iterator"
 )
 
 /*
@@ -22,16 +28,52 @@ UploadFile uploads a file to Google Cloud Storage and returns an error if one oc
 Note: UploadFile requires that a default bucket be set in bifrost.BridgeConfig.
 */
 func (g *GoogleCloudStorage) UploadFile(fileFace interface{}) (*types.UploadedFile, error) {
+	return g.UploadFileContext(context.Background(), fileFace)
+}
+
+// UploadFileContext is like UploadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (g *GoogleCloudStorage) UploadFileContext(ctx context.Context, fileFace interface{}) (*types.UploadedFile, error) {
+	bFile, err := types.AsFile(fileFace)
+	if err != nil {
+		return nil, err
+	}
+	return g.Upload(ctx, bFile)
+}
+
+// Upload uploads a file to Google Cloud Storage and returns an error if one occurs. It is the typed equivalent of UploadFileContext.
+// Uploads failing with a transient error are retried according to the bridge RetryPolicy.
+func (g *GoogleCloudStorage) Upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	var uploadedFile *types.UploadedFile
+	err := retry.Upload(ctx, g.RetryPolicy, g.IsRetryable, bFile, func() error {
+		var err error
+		uploadedFile, err = g.upload(ctx, bFile)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uploadedFile, nil
+}
 
-	// assert that the fileFace is of type bifrost.File
-	bFile, ok := fileFace.(types.File)
-	if !ok {
+/*
+UploadJSON marshals doc.Data and uploads it to Google Cloud Storage as a JSON document with the application/json content type, unless
+another content type is set in doc.Options, and returns an error if one occurs.
+
+Note: UploadJSON requires doc.Filename.
+*/
+func (g *GoogleCloudStorage) UploadJSON(ctx context.Context, doc types.JSONFile) (*types.UploadedFile, error) {
+	bFile, err := doc.File()
+	if err != nil {
 		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("argument must be of type bifrost.File"),
-			ErrorCode: errors.ErrBadRequest,
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
 		}
 	}
+	return g.Upload(ctx, bFile)
+}
 
+// upload makes a single attempt at uploading a file to Google Cloud Storage.
+func (g *GoogleCloudStorage) upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
 	// validate struct
 	if err := bFile.Validate(); err != nil {
 		return nil, &errors.BifrostError{
@@ -46,10 +88,8 @@ func (g *GoogleCloudStorage) UploadFile(fileFace interface{}) (*types.UploadedFi
 			ErrorCode: errors.ErrClientError,
 		}
 	}
-	// create context and add timeout if default timeout is set
-	var ctx context.Context
+	// add timeout if default timeout is set
 	var cancel context.CancelFunc
-	ctx = context.Background()
 	if g.DefaultTimeout > 0 {
 		ctx, cancel = context.WithTimeout(ctx, time.Duration(g.DefaultTimeout)*time.Second)
 		defer cancel()
@@ -85,7 +125,7 @@ func (g *GoogleCloudStorage) UploadFile(fileFace interface{}) (*types.UploadedFi
 	// Upload file to Google Cloud Storage
 	obj := g.Client.Bucket(g.DefaultBucket).Object(bFile.Filename)
 	wc := obj.NewWriter(ctx)
-	if _, err := io.Copy(wc, bFile.Handle); err != nil {
+	if _, err := io.Copy(wc, progress.Reader(bFile.Handle, bFile)); err != nil {
 		return nil, &errors.BifrostError{
 			Err:       err,
 			ErrorCode: errors.ErrFileOperationFailed,
@@ -99,6 +139,9 @@ func (g *GoogleCloudStorage) UploadFile(fileFace interface{}) (*types.UploadedFi
 		}
 	}
 
+	// work on a copy of the options so that the caller's map is never written to
+	bFile = bFile.Clone()
+
 	// if no ACL is set, check if w.PublicRead is true
 	if bFile.Options[config.OptACL] == nil && g.PublicRead {
 		// set public read permissions
@@ -173,16 +216,20 @@ to the []UploadedFile.Error and also logged when debug is enabled while the rest
 Note: for some providers, UploadMultiFile requires that a default bucket be set in bifrost.BridgeConfig.
 */
 func (g *GoogleCloudStorage) UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error) {
+	return g.UploadMultiFileContext(context.Background(), multiFace)
+}
 
-	// assert that the multiFace is of type bifrost.File
-	multiFile, ok := multiFace.(types.MultiFile)
-	if !ok {
-		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("argument must be of type bifrost.MultiFile"),
-			ErrorCode: errors.ErrBadRequest,
-		}
+// UploadMultiFileContext is like UploadMultiFile but uses ctx for cancellation and deadlines of every upload.
+func (g *GoogleCloudStorage) UploadMultiFileContext(ctx context.Context, multiFace interface{}) ([]*types.UploadedFile, error) {
+	multiFile, err := types.AsMultiFile(multiFace)
+	if err != nil {
+		return nil, err
 	}
+	return g.UploadMulti(ctx, multiFile)
+}
 
+// UploadMulti uploads multiple files to Google Cloud Storage and returns an error if one occurs. It is the typed equivalent of UploadMultiFileContext.
+func (g *GoogleCloudStorage) UploadMulti(ctx context.Context, multiFile types.MultiFile) ([]*types.UploadedFile, error) {
 	// validate struct
 	if err := multiFile.Validate(); err != nil {
 		return nil, &errors.BifrostError{
@@ -198,31 +245,26 @@ func (g *GoogleCloudStorage) UploadMultiFile(multiFace interface{}) ([]*types.Up
 		}
 	}
 
-	uploadedFiles := make([]*types.UploadedFile, 0, len(multiFile.Files))
+	uploadedFiles := make([]*types.UploadedFile, len(multiFile.Files))
+	batch := progress.NewBatch(multiFile)
 
-	// @TODO: add concurrency when UseAsync is true
-	for _, file := range multiFile.Files {
-		if multiFile.GlobalOptions != nil {
-			// merge global options with file options
-			for k, v := range multiFile.GlobalOptions {
-				// don't override if file has option already
-				if _, ok := file.Options[k]; !ok {
-					file.Options[k] = v
-				}
-			}
-		}
+	// upload concurrently when UseAsync is true, writing each result at its file's index to keep the order of multiFile.Files
+	pool.Run(len(multiFile.Files), pool.Size(g.UseAsync, g.MaxConcurrency), func(i int) {
+		// merge global options into a copy of the file options so that shared maps are never written to
+		file := batch.Track(i, multiFile.Merge(i))
 
-		uploadedFile, err := g.UploadFile(file)
+		uploadedFile, err := g.Upload(ctx, file)
+		batch.Done(i, err)
 		if err != nil {
 			if g.EnableDebug {
 				// log failed file and continue
 				log.Printf("Upload for file at path %s failed with err: %s\n", file.Path, err.Error())
 			}
-			uploadedFiles = append(uploadedFiles, &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path})
-			continue
+			uploadedFiles[i] = &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path}
+			return
 		}
-		uploadedFiles = append(uploadedFiles, uploadedFile)
-	}
+		uploadedFiles[i] = uploadedFile
+	})
 
 	return uploadedFiles, nil
 }
@@ -250,6 +292,8 @@ func (g *GoogleCloudStorage) Config() *types.BridgeConfig {
 		DefaultTimeout:  g.DefaultTimeout,
 		EnableDebug:     g.EnableDebug,
 		UseAsync:        g.UseAsync,
+		MaxConcurrency:  g.MaxConcurrency,
+		RetryPolicy:     g.RetryPolicy,
 	}
 }
 
@@ -258,38 +302,112 @@ func (g *GoogleCloudStorage) IsConnected() bool {
 	return g.Client != nil
 }
 
+// Ping checks that the default Google Cloud Storage bucket can be reached with the configured credentials and returns an error if one occurs.
+func (g *GoogleCloudStorage) Ping(ctx context.Context) error {
+	if !g.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active Google Cloud Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if g.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(g.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	if _, err := g.Client.Bucket(g.DefaultBucket).Attrs(ctx); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	return nil
+}
+
 /*
-UploadFolder uploads a folder to the provider storage and returns an error if one occurs.
+UploadFolder uploads every file in a local folder to Google Cloud Storage and returns an error if one occurs.
+Each file is stored under its path relative to Folder.Path, behind Folder.Prefix, and Folder.Options are merged into
+each file's options the same way as MultiFile.GlobalOptions. Like UploadMultiFile, one UploadedFile is returned per file with
+any failure recorded in its Error field.
 
-Note: for some providers, UploadFolder requires that a default bucket be set in bifrost.BridgeConfig.
+Note: UploadFolder requires that a default bucket be set in bifrost.BridgeConfig.
 */
 func (g *GoogleCloudStorage) UploadFolder(foldFace interface{}) ([]*types.UploadedFile, error) {
-	return nil, nil
+	return g.UploadFolderContext(context.Background(), foldFace)
+}
+
+// UploadFolderContext is like UploadFolder but uses ctx for cancellation and deadlines of every upload.
+func (g *GoogleCloudStorage) UploadFolderContext(ctx context.Context, foldFace interface{}) ([]*types.UploadedFile, error) {
+	folder, err := types.AsFolder(foldFace)
+	if err != nil {
+		return nil, err
+	}
+	return g.UploadDir(ctx, folder)
+}
+
+// UploadDir uploads every file in a local folder to Google Cloud Storage and returns an error if one occurs. It is the typed equivalent of UploadFolderContext.
+func (g *GoogleCloudStorage) UploadDir(ctx context.Context, folder types.Folder) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := folder.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	files, err := walk.Files(folder)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if len(files) == 0 {
+		return []*types.UploadedFile{}, nil
+	}
+
+	return g.UploadMulti(ctx, types.MultiFile{
+		Files:         files,
+		GlobalOptions: folder.Options,
+	})
 }
 
 /*
 DeleteFile deletes a file from Google Cloud Storage and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
 
-Note: DeleteFile requires that a default bucket be set in bifrost.BridgeConfig.
+Note: DeleteFile requires that a default bucket be set in bifrost.BridgeConfig unless DeleteFile.Buckets is set.
 */
 func (g *GoogleCloudStorage) DeleteFile(fileFace interface{}) error {
+	return g.DeleteFileContext(context.Background(), fileFace)
+}
 
-	// assert that the fileFace is of type bifrost.File
-	bFile, ok := fileFace.(types.File)
-	if !ok {
-		return &errors.BifrostError{
-			Err:       fmt.Errorf("argument must be of type bifrost.File"),
-			ErrorCode: errors.ErrBadRequest,
-		}
+// DeleteFileContext is like DeleteFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (g *GoogleCloudStorage) DeleteFileContext(ctx context.Context, fileFace interface{}) error {
+	dFile, err := types.AsDeleteFile(fileFace)
+	if err != nil {
+		return err
 	}
+	return g.Delete(ctx, dFile)
+}
 
+// Delete deletes a file from Google Cloud Storage and returns an error if one occurs. It is the typed equivalent of DeleteFileContext.
+func (g *GoogleCloudStorage) Delete(ctx context.Context, dFile types.DeleteFile) error {
 	// validate struct
-	if err := bFile.Validate(); err != nil {
+	if err := dFile.Validate(); err != nil {
 		return &errors.BifrostError{
 			Err:       err,
 			ErrorCode: errors.ErrInvalidParameters,
 		}
 	}
+	// files are stored by name, so a CID alone does not identify one
+	if dFile.Filename == "" {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("file.Filename is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
 
 	if !g.IsConnected() {
 		return &errors.BifrostError{
@@ -298,25 +416,297 @@ func (g *GoogleCloudStorage) DeleteFile(fileFace interface{}) error {
 		}
 	}
 
-	// create context and add timeout if default timeout is set
-	var ctx context.Context
+	// add timeout if default timeout is set
 	var cancel context.CancelFunc
-	ctx = context.Background()
 	if g.DefaultTimeout > 0 {
 		ctx, cancel = context.WithTimeout(ctx, time.Duration(g.DefaultTimeout)*time.Second)
 		defer cancel()
 	}
 
-	if bFile.Filename != "" {
+	buckets := dFile.Buckets
+	if len(buckets) == 0 {
+		buckets = []string{g.DefaultBucket}
+	}
 
-		obj := g.Client.Bucket(g.DefaultBucket).Object(bFile.Filename)
+	for _, bucket := range buckets {
+		obj := g.Client.Bucket(bucket).Object(dFile.Filename)
 
 		if err := obj.Delete(ctx); err != nil {
+			if errors.Is(err, storage.ErrObjectNotExist) {
+				return &errors.BifrostError{
+					Err:       fmt.Errorf("file does not exist: %s/%s", bucket, dFile.Filename),
+					ErrorCode: errors.ErrNotFound,
+				}
+			}
 			return &errors.BifrostError{
 				Err:       err,
-				ErrorCode: errors.ErrUnauthorized,
+				ErrorCode: errors.ErrFileOperationFailed,
 			}
 		}
 	}
 	return nil
 }
+
+/*
+OpenReader opens a file in Google Cloud Storage for reading and returns its contents along with its attributes.
+If the file does not exist, an error with the code ErrNotFound is returned.
+The caller must close the returned reader.
+
+Note: OpenReader requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (g *GoogleCloudStorage) OpenReader(name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	return g.OpenReaderContext(context.Background(), name)
+}
+
+// OpenReaderContext is like OpenReader but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx
+// and covers reading the returned reader.
+func (g *GoogleCloudStorage) OpenReaderContext(ctx context.Context, name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	if name == "" {
+		return nil, nil, &errors.BifrostError{
+			Err:       fmt.Errorf("name is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !g.IsConnected() {
+		return nil, nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Google Cloud Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// the context must outlive this call as the body is read by the caller, so it is cancelled when the reader is closed
+	var cancel context.CancelFunc
+	if g.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(g.DefaultTimeout)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	obj := g.Client.Bucket(g.DefaultBucket).Object(name)
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		cancel()
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s/%s", g.DefaultBucket, name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	// read the generation we just got the attributes of, in case the object is replaced in between
+	reader, err := obj.Generation(attrs.Generation).NewReader(ctx)
+	if err != nil {
+		cancel()
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s/%s", g.DefaultBucket, name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	return stream.CancelOnClose(reader, cancel), objectInfo(attrs), nil
+}
+
+/*
+DownloadFile downloads a file from Google Cloud Storage to localPath and returns its attributes.
+localPath is only replaced once the whole file has been downloaded.
+
+Note: DownloadFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (g *GoogleCloudStorage) DownloadFile(name, localPath string) (*types.ObjectInfo, error) {
+	return g.DownloadFileContext(context.Background(), name, localPath)
+}
+
+// DownloadFileContext is like DownloadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (g *GoogleCloudStorage) DownloadFileContext(ctx context.Context, name, localPath string) (*types.ObjectInfo, error) {
+	if localPath == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("localPath is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	rc, info, err := g.OpenReaderContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if info.Size, err = stream.SaveFile(localPath, rc); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return info, nil
+}
+
+/*
+ListFiles lists the files in the default Google Cloud Storage bucket one page at a time and returns an error if one occurs.
+Pass ListResult.NextToken as ListOptions.ContinuationToken to list the following page.
+
+Note: ListFiles requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (g *GoogleCloudStorage) ListFiles(opts types.ListOptions) (*types.ListResult, error) {
+	return g.ListFilesContext(context.Background(), opts)
+}
+
+// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (g *GoogleCloudStorage) ListFilesContext(ctx context.Context, opts types.ListOptions) (*types.ListResult, error) {
+
+	// validate struct
+	if err := opts.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !g.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Google Cloud Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if g.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(g.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		pageSize = config.DefaultPageSize
+	}
+
+	it := g.Client.Bucket(g.DefaultBucket).Objects(ctx, &storage.Query{
+		Prefix:    opts.Prefix,
+		Delimiter: opts.Delimiter,
+	})
+	var attrs []*storage.ObjectAttrs
+	next, err := iterator.NewPager(it, pageSize, opts.ContinuationToken).NextPage(&attrs)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	result := &types.ListResult{
+		Files:     make([]*types.ObjectInfo, 0, len(attrs)),
+		NextToken: next,
+	}
+	for _, a := range attrs {
+		// with a delimiter, grouped names come back as attributes with only the prefix set
+		if a.Prefix != "" {
+			result.Prefixes = append(result.Prefixes, a.Prefix)
+			continue
+		}
+		result.Files = append(result.Files, objectInfo(a))
+	}
+	return result, nil
+}
+
+/*
+StatFile returns the attributes of a file in the default Google Cloud Storage bucket and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
+
+Note: StatFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (g *GoogleCloudStorage) StatFile(name string) (*types.ObjectInfo, error) {
+	return g.StatFileContext(context.Background(), name)
+}
+
+// StatFileContext is like StatFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (g *GoogleCloudStorage) StatFileContext(ctx context.Context, name string) (*types.ObjectInfo, error) {
+	if name == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("name is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !g.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Google Cloud Storage client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if g.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(g.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	attrs, err := g.Client.Bucket(g.DefaultBucket).Object(name).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s/%s", g.DefaultBucket, name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return objectInfo(attrs), nil
+}
+
+/*
+Exists returns true if a file exists in the default Google Cloud Storage bucket and returns an error if the lookup fails.
+
+Note: Exists requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (g *GoogleCloudStorage) Exists(name string) (bool, error) {
+	return g.ExistsContext(context.Background(), name)
+}
+
+// ExistsContext is like Exists but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (g *GoogleCloudStorage) ExistsContext(ctx context.Context, name string) (bool, error) {
+	if _, err := g.StatFileContext(ctx, name); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// objectInfo converts Google Cloud Storage object attributes to a types.ObjectInfo.
+func objectInfo(attrs *storage.ObjectAttrs) *types.ObjectInfo {
+	return &types.ObjectInfo{
+		Name:           attrs.Name,
+		Bucket:         attrs.Bucket,
+		Size:           attrs.Size,
+		ContentType:    attrs.ContentType,
+		Metadata:       attrs.Metadata,
+		ETag:           attrs.Etag,
+		Created:        attrs.Created,
+		Updated:        attrs.Updated,
+		URL:            fmt.Sprintf(config.URLGoogleCloudStorage, attrs.Bucket, attrs.Name),
+		ProviderObject: attrs,
+	}
+}
+
+// IsRetryable returns true if err is a transient Google Cloud Storage failure, such as a rate limited request, a 5xx
+// response or a dropped connection, that is worth retrying.
+func (g *GoogleCloudStorage) IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return storage.ShouldRetry(err) || retry.IsTransient(err)
+}
@@ -25,4 +25,8 @@ type GoogleCloudStorage struct {
 	PublicRead bool
 	// UseAsync enables asynchronous operations with go routines.
 	UseAsync bool
+	// MaxConcurrency is the maximum number of concurrent uploads when UseAsync is enabled.
+	MaxConcurrency int
+	// RetryPolicy configures how failed uploads are retried. Uploads are not retried when it is nil.
+	RetryPolicy *types.RetryPolicy
 }
@@ -0,0 +1,878 @@
+// Bifrost interface for the local filesystem
+package local
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/page"
+	"github.com/opensaucerer/bifrost/shared/pool"
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/stream"
+	"github.com/opensaucerer/bifrost/shared/types"
+	"github.com/opensaucerer/bifrost/shared/walk"
+)
+
+/*
+UploadFile uploads a file to the local filesystem and returns an error if one occurs.
+
+Note: for some providers, UploadFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (l *LocalStorage) UploadFile(fileFace interface{}) (*types.UploadedFile, error) {
+	return l.UploadFileContext(context.Background(), fileFace)
+}
+
+// UploadFileContext is like UploadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (l *LocalStorage) UploadFileContext(ctx context.Context, fileFace interface{}) (*types.UploadedFile, error) {
+	bFile, err := types.AsFile(fileFace)
+	if err != nil {
+		return nil, err
+	}
+	return l.Upload(ctx, bFile)
+}
+
+// Upload uploads a file to the local filesystem and returns an error if one occurs. It is the typed equivalent of UploadFileContext.
+// Uploads failing with a transient error are retried according to the bridge RetryPolicy.
+func (l *LocalStorage) Upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	var uploadedFile *types.UploadedFile
+	err := retry.Upload(ctx, l.RetryPolicy, l.IsRetryable, bFile, func() error {
+		var err error
+		uploadedFile, err = l.upload(ctx, bFile)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uploadedFile, nil
+}
+
+/*
+UploadJSON marshals doc.Data and uploads it to local storage as a JSON document with the application/json content type, unless
+another content type is set in doc.Options, and returns an error if one occurs.
+
+Note: UploadJSON requires doc.Filename.
+*/
+func (l *LocalStorage) UploadJSON(ctx context.Context, doc types.JSONFile) (*types.UploadedFile, error) {
+	bFile, err := doc.File()
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+	return l.Upload(ctx, bFile)
+}
+
+// upload makes a single attempt at uploading a file to the local filesystem.
+func (l *LocalStorage) upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	// validate struct
+	if err := bFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !l.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active local storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if l.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(l.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	if bFile.Path != "" {
+		// verify that file exists
+		if _, err := os.Stat(bFile.Path); os.IsNotExist(err) {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s", bFile.Path),
+				ErrorCode: errors.ErrBadRequest,
+			}
+		}
+		// open file
+		file, err := os.Open(bFile.Path)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+		// close file
+		defer file.Close()
+
+		bFile.Handle = file
+
+		// ensure filename
+		if bFile.Filename == "" {
+			bFile.Filename = filepath.Base(bFile.Path)
+		}
+	}
+
+	dest, err := l.objectPath(l.DefaultBucket, bFile.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// work on a copy of the options so that the caller's map is never written to
+	bFile = bFile.Clone()
+
+	// if no ACL is set, check if l.PublicRead is true
+	if bFile.Options[config.OptACL] == nil && l.PublicRead {
+		// set public read permissions
+		bFile.Options[config.OptACL] = config.ACLPublicRead
+	}
+
+	meta := sidecar{
+		ContentType: mime.TypeByExtension(path.Ext(bFile.Filename)),
+		Created:     time.Now().UTC(),
+	}
+	// configure upload options
+	for k, v := range bFile.Options {
+		switch k {
+		// acl
+		case config.OptACL:
+			if v, ok := v.(string); ok && (v == config.ACLPublicRead || v == config.ACLPrivate) {
+				meta.ACL = v
+			}
+		// content type
+		case config.OptContentType:
+			if v, ok := v.(string); ok {
+				meta.ContentType = v
+			}
+		// metadata
+		case config.OptMetadata:
+			if v, ok := v.(map[string]string); ok {
+				meta.Metadata = v
+			}
+		}
+	}
+	if meta.ContentType == "" {
+		meta.ContentType = "application/octet-stream"
+	}
+
+	// write the file and its sidecar to temporary files and move them into place once complete
+	hash := md5.New()
+	size, err := l.writeAtomic(l.DefaultBucket, dest, io.TeeReader(&ctxReader{ctx: ctx, r: progress.Reader(bFile.Handle, bFile)}, hash))
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	meta.ETag = hex.EncodeToString(hash.Sum(nil))
+	if err := l.writeSidecar(l.DefaultBucket, bFile.Filename, meta); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	fi, err := os.Stat(dest)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return &types.UploadedFile{
+		Name:           bFile.Filename,
+		Bucket:         l.DefaultBucket,
+		Path:           bFile.Path,
+		Size:           size,
+		URL:            l.url(l.DefaultBucket, bFile.Filename),
+		Preview:        l.url(l.DefaultBucket, bFile.Filename),
+		ProviderObject: fi,
+	}, nil
+}
+
+/*
+UploadMultiFile uploads mutliple files to the local filesystem and returns an error if one occurs. If any of the uploads fail, the error is appended
+to the []UploadedFile.Error and also logged when debug is enabled while the rest of the uploads continue.
+
+Note: for some providers, UploadMultiFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (l *LocalStorage) UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error) {
+	return l.UploadMultiFileContext(context.Background(), multiFace)
+}
+
+// UploadMultiFileContext is like UploadMultiFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies to each upload.
+func (l *LocalStorage) UploadMultiFileContext(ctx context.Context, multiFace interface{}) ([]*types.UploadedFile, error) {
+	multiFile, err := types.AsMultiFile(multiFace)
+	if err != nil {
+		return nil, err
+	}
+	return l.UploadMulti(ctx, multiFile)
+}
+
+// UploadMulti uploads multiple files to the local filesystem. It is the typed equivalent of UploadMultiFileContext.
+func (l *LocalStorage) UploadMulti(ctx context.Context, multiFile types.MultiFile) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := multiFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !l.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active local storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+
+	uploadedFiles := make([]*types.UploadedFile, len(multiFile.Files))
+	batch := progress.NewBatch(multiFile)
+
+	// upload concurrently when UseAsync is true, writing each result at its file's index to keep the order of multiFile.Files
+	pool.Run(len(multiFile.Files), pool.Size(l.UseAsync, l.MaxConcurrency), func(i int) {
+		// merge global options into a copy of the file options so that shared maps are never written to
+		file := batch.Track(i, multiFile.Merge(i))
+
+		uploadedFile, err := l.Upload(ctx, file)
+		batch.Done(i, err)
+		if err != nil {
+			if l.EnableDebug {
+				// log failed file and continue
+				log.Printf("Upload for file at path %s failed with err: %s\n", file.Path, err.Error())
+			}
+			uploadedFiles[i] = &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path}
+			return
+		}
+		uploadedFiles[i] = uploadedFile
+	})
+
+	return uploadedFiles, nil
+}
+
+// Config returns the local storage configuration.
+func (l *LocalStorage) Config() *types.BridgeConfig {
+	return &types.BridgeConfig{
+		DefaultBucket:  l.DefaultBucket,
+		Root:           l.Root,
+		BaseURL:        l.BaseURL,
+		DefaultTimeout: l.DefaultTimeout,
+		EnableDebug:    l.EnableDebug,
+		Provider:       l.Provider,
+		PublicRead:     l.PublicRead,
+		UseAsync:       l.UseAsync,
+		MaxConcurrency: l.MaxConcurrency,
+		RetryPolicy:    l.RetryPolicy,
+	}
+}
+
+/*
+Disconnect releases the local storage and returns an error if one occurs. Files on disk are left untouched.
+
+Disconnect should only be called when the storage is no longer needed.
+*/
+func (l *LocalStorage) Disconnect() error {
+	if l.IsConnected() {
+		l.Root = ""
+	}
+	return nil
+}
+
+// IsConnected returns true if there is an active local storage.
+func (l *LocalStorage) IsConnected() bool {
+	return l.Root != ""
+}
+
+// Ping checks that the default bucket directory exists and returns an error if one occurs.
+func (l *LocalStorage) Ping(ctx context.Context) error {
+	if !l.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active local storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+
+	info, err := os.Stat(l.bucketPath(l.DefaultBucket))
+	if err == nil && !info.IsDir() {
+		err = fmt.Errorf("not a directory: %s", l.bucketPath(l.DefaultBucket))
+	}
+	if err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	return nil
+}
+
+/*
+UploadFolder uploads the files of a local folder, recursively, to the local filesystem and returns an error if one occurs.
+Files are stored under their path relative to the folder, joined to folder.Prefix. If any of the uploads fail, the error is
+appended to the []UploadedFile.Error while the rest of the uploads continue.
+
+Note: for some providers, UploadFolder requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (l *LocalStorage) UploadFolder(foldFace interface{}) ([]*types.UploadedFile, error) {
+	return l.UploadFolderContext(context.Background(), foldFace)
+}
+
+// UploadFolderContext is like UploadFolder but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies to each upload.
+func (l *LocalStorage) UploadFolderContext(ctx context.Context, foldFace interface{}) ([]*types.UploadedFile, error) {
+	folder, err := types.AsFolder(foldFace)
+	if err != nil {
+		return nil, err
+	}
+	return l.UploadDir(ctx, folder)
+}
+
+// UploadDir uploads the files of a local folder to the local filesystem. It is the typed equivalent of UploadFolderContext.
+func (l *LocalStorage) UploadDir(ctx context.Context, folder types.Folder) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := folder.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	files, err := walk.Files(folder)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if len(files) == 0 {
+		return []*types.UploadedFile{}, nil
+	}
+
+	return l.UploadMulti(ctx, types.MultiFile{
+		Files:         files,
+		GlobalOptions: folder.Options,
+	})
+}
+
+/*
+DeleteFile deletes a file from the local filesystem and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
+
+Note: for some providers, DeleteFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (l *LocalStorage) DeleteFile(fileFace interface{}) error {
+	return l.DeleteFileContext(context.Background(), fileFace)
+}
+
+// DeleteFileContext is like DeleteFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (l *LocalStorage) DeleteFileContext(ctx context.Context, fileFace interface{}) error {
+	dFile, err := types.AsDeleteFile(fileFace)
+	if err != nil {
+		return err
+	}
+	return l.Delete(ctx, dFile)
+}
+
+// Delete deletes a file from the local filesystem. It is the typed equivalent of DeleteFileContext.
+func (l *LocalStorage) Delete(ctx context.Context, dFile types.DeleteFile) error {
+	// validate struct
+	if err := dFile.Validate(); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+	// files are stored by name, so a CID alone does not identify one
+	if dFile.Filename == "" {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("file.Filename is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !l.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active local storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if l.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(l.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	buckets := dFile.Buckets
+	if len(buckets) == 0 {
+		buckets = []string{l.DefaultBucket}
+	}
+
+	for _, bucket := range buckets {
+		if err := ctx.Err(); err != nil {
+			return &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+
+		dest, err := l.objectPath(bucket, dFile.Filename)
+		if err != nil {
+			return err
+		}
+		// only files can be deleted, directories just hold them
+		if fi, err := os.Lstat(dest); err == nil && !fi.Mode().IsRegular() {
+			return &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s/%s", bucket, dFile.Filename),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		if err := os.Remove(dest); err != nil {
+			if os.IsNotExist(err) {
+				return &errors.BifrostError{
+					Err:       fmt.Errorf("file does not exist: %s/%s", bucket, dFile.Filename),
+					ErrorCode: errors.ErrNotFound,
+				}
+			}
+			return &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+		if err := os.Remove(l.sidecarPath(bucket, dFile.Filename)); err != nil && !os.IsNotExist(err) {
+			return &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+
+		// directories only exist to hold files, so remove the ones left empty
+		l.removeEmptyDirs(l.bucketPath(bucket), filepath.Dir(dest))
+		l.removeEmptyDirs(filepath.Join(l.bucketPath(bucket), metaDir, sidecarDir), filepath.Dir(l.sidecarPath(bucket, dFile.Filename)))
+	}
+	return nil
+}
+
+/*
+OpenReader opens a file stored on the local filesystem for reading and returns its attributes along with it.
+The caller must close the returned reader.
+
+Note: for some providers, OpenReader requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (l *LocalStorage) OpenReader(name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	return l.OpenReaderContext(context.Background(), name)
+}
+
+// OpenReaderContext is like OpenReader but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx
+// and covers reading the returned reader until it is closed.
+func (l *LocalStorage) OpenReaderContext(ctx context.Context, name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	if !l.IsConnected() {
+		return nil, nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active local storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+
+	info, err := l.StatFileContext(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dest, _ := l.objectPath(l.DefaultBucket, name)
+	file, err := os.Open(dest)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s", name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	// the context is released once the reader is closed
+	var cancel context.CancelFunc
+	if l.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(l.DefaultTimeout)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	rc := struct {
+		io.Reader
+		io.Closer
+	}{&ctxReader{ctx: ctx, r: file}, file}
+	return stream.CancelOnClose(rc, cancel), info, nil
+}
+
+/*
+DownloadFile downloads a file stored on the local filesystem to localPath and returns its attributes.
+The file is written to a temporary file first and moved to localPath once complete.
+
+Note: for some providers, DownloadFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (l *LocalStorage) DownloadFile(name, localPath string) (*types.ObjectInfo, error) {
+	return l.DownloadFileContext(context.Background(), name, localPath)
+}
+
+// DownloadFileContext is like DownloadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (l *LocalStorage) DownloadFileContext(ctx context.Context, name, localPath string) (*types.ObjectInfo, error) {
+	if localPath == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("localPath is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	rc, info, err := l.OpenReaderContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if info.Size, err = stream.SaveFile(localPath, rc); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return info, nil
+}
+
+/*
+ListFiles lists the files stored on the local filesystem page by page and returns an error if one occurs.
+Pass the returned NextToken as opts.ContinuationToken to list the next page.
+
+Note: for some providers, ListFiles requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (l *LocalStorage) ListFiles(opts types.ListOptions) (*types.ListResult, error) {
+	return l.ListFilesContext(context.Background(), opts)
+}
+
+// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (l *LocalStorage) ListFilesContext(ctx context.Context, opts types.ListOptions) (*types.ListResult, error) {
+	// validate struct
+	if err := opts.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !l.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active local storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if l.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(l.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	root := l.bucketPath(l.DefaultBucket)
+	var names []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// hide the sidecar metadata and in-flight uploads
+			if p == filepath.Join(root, metaDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	files, prefixes, next := page.Names(names, opts)
+	result := &types.ListResult{
+		Files:     make([]*types.ObjectInfo, 0, len(files)),
+		Prefixes:  prefixes,
+		NextToken: next,
+	}
+	for _, name := range files {
+		info, err := l.objectInfo(l.DefaultBucket, name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// deleted while listing
+				continue
+			}
+			return nil, err
+		}
+		result.Files = append(result.Files, info)
+	}
+	return result, nil
+}
+
+/*
+StatFile returns the attributes of a file stored on the local filesystem and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
+
+Note: for some providers, StatFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (l *LocalStorage) StatFile(name string) (*types.ObjectInfo, error) {
+	return l.StatFileContext(context.Background(), name)
+}
+
+// StatFileContext is like StatFile but uses ctx for cancellation and deadlines.
+func (l *LocalStorage) StatFileContext(ctx context.Context, name string) (*types.ObjectInfo, error) {
+	if !l.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active local storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return l.objectInfo(l.DefaultBucket, name)
+}
+
+// Exists returns true if a file is stored on the local filesystem and returns an error if the lookup fails.
+func (l *LocalStorage) Exists(name string) (bool, error) {
+	return l.ExistsContext(context.Background(), name)
+}
+
+// ExistsContext is like Exists but uses ctx for cancellation and deadlines.
+func (l *LocalStorage) ExistsContext(ctx context.Context, name string) (bool, error) {
+	if _, err := l.StatFileContext(ctx, name); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// IsRetryable returns false since local filesystem failures are not expected to go away when tried again.
+func (l *LocalStorage) IsRetryable(err error) bool {
+	return false
+}
+
+// Read reads from the underlying reader unless the context is done.
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// bucketPath returns the directory of bucket.
+func (l *LocalStorage) bucketPath(bucket string) string {
+	return filepath.Join(l.Root, filepath.FromSlash(bucket))
+}
+
+// objectPath returns the path of the file stored as name in bucket, or an error if name or bucket would escape Root.
+func (l *LocalStorage) objectPath(bucket, name string) (string, error) {
+	if err := validName(bucket, true); err != nil {
+		return "", &errors.BifrostError{
+			Err:       fmt.Errorf("invalid bucket %q: %s", bucket, err.Error()),
+			ErrorCode: errors.ErrInvalidBucket,
+		}
+	}
+	if err := validName(name, false); err != nil {
+		return "", &errors.BifrostError{
+			Err:       fmt.Errorf("invalid filename %q: %s", name, err.Error()),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+	return filepath.Join(l.bucketPath(bucket), filepath.FromSlash(name)), nil
+}
+
+// sidecarPath returns the path of the metadata file of the file stored as name in bucket.
+func (l *LocalStorage) sidecarPath(bucket, name string) string {
+	return filepath.Join(l.bucketPath(bucket), metaDir, sidecarDir, filepath.FromSlash(name)+sidecarExt)
+}
+
+// validName returns an error if name is not a clean, relative, slash separated path outside of the metadata directory.
+// An empty name is only valid if allowEmpty is true.
+func validName(name string, allowEmpty bool) error {
+	if name == "" {
+		if allowEmpty {
+			return nil
+		}
+		return fmt.Errorf("name is empty")
+	}
+	if strings.Contains(name, `\`) || path.IsAbs(name) || path.Clean(name) != name || name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("name must be a clean relative path")
+	}
+	if name == metaDir || strings.HasPrefix(name, metaDir+"/") {
+		return fmt.Errorf("%s is reserved", metaDir)
+	}
+	return nil
+}
+
+// writeAtomic writes r to a temporary file in the metadata directory of bucket and renames it to dest once complete,
+// so that dest is never seen partially written.
+func (l *LocalStorage) writeAtomic(bucket, dest string, r io.Reader) (int64, error) {
+	tmpDir := filepath.Join(l.bucketPath(bucket), metaDir, tempDir)
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(tmpDir, "upload-*")
+	if err != nil {
+		return 0, err
+	}
+	// clean up the temporary file if it is never renamed
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return n, err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return n, err
+	}
+	return n, os.Rename(tmp.Name(), dest)
+}
+
+// writeSidecar atomically stores the metadata of the file stored as name in bucket.
+func (l *LocalStorage) writeSidecar(bucket, name string, meta sidecar) error {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	_, err = l.writeAtomic(bucket, l.sidecarPath(bucket, name), strings.NewReader(string(b)))
+	return err
+}
+
+// readSidecar returns the metadata of the file stored as name in bucket. A missing sidecar yields empty metadata.
+func (l *LocalStorage) readSidecar(bucket, name string) (sidecar, error) {
+	var meta sidecar
+	b, err := os.ReadFile(l.sidecarPath(bucket, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return meta, nil
+		}
+		return meta, err
+	}
+	return meta, json.Unmarshal(b, &meta)
+}
+
+// objectInfo returns the attributes of the file stored as name in bucket.
+func (l *LocalStorage) objectInfo(bucket, name string) (*types.ObjectInfo, error) {
+	dest, err := l.objectPath(bucket, name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(dest)
+	if err != nil || !fi.Mode().IsRegular() {
+		if err == nil || os.IsNotExist(err) {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s", name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	meta, err := l.readSidecar(bucket, name)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if meta.ContentType == "" {
+		meta.ContentType = mime.TypeByExtension(path.Ext(name))
+	}
+	created := meta.Created
+	if created.IsZero() {
+		created = fi.ModTime()
+	}
+
+	return &types.ObjectInfo{
+		Name:           name,
+		Bucket:         bucket,
+		Size:           fi.Size(),
+		ContentType:    meta.ContentType,
+		Metadata:       meta.Metadata,
+		ETag:           meta.ETag,
+		Created:        created,
+		Updated:        fi.ModTime(),
+		URL:            l.url(bucket, name),
+		ProviderObject: fi,
+	}, nil
+}
+
+// url returns the URL of the file stored as name in bucket.
+func (l *LocalStorage) url(bucket, name string) string {
+	if l.BaseURL == "" {
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(l.bucketPath(bucket), filepath.FromSlash(name)))}).String()
+	}
+
+	segments := strings.Split(strings.Trim(bucket+"/"+name, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.TrimSuffix(l.BaseURL, "/") + "/" + strings.Join(segments, "/")
+}
+
+// removeEmptyDirs removes dir and its parents while they are empty, stopping at root.
+func (l *LocalStorage) removeEmptyDirs(root, dir string) {
+	for dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,236 @@
+package local_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opensaucerer/bifrost"
+)
+
+var (
+	bridge bifrost.RainbowBridge
+	err    error
+	root   string
+)
+
+func setup(t *testing.T) {
+	root = t.TempDir()
+
+	bridge, err = bifrost.NewRainbowBridge(&bifrost.BridgeConfig{
+		DefaultBucket:  "bifrost",
+		DefaultTimeout: 10,
+		Provider:       bifrost.LocalStorage,
+		EnableDebug:    true,
+		PublicRead:     true,
+		Root:           root,
+		BaseURL:        "https://files.example.com/",
+	})
+	if err != nil {
+		t.Fatal(err.(bifrost.Error).Code(), err)
+	}
+
+	t.Logf("Connected to %s\n", bridge.Config().Provider)
+}
+
+func teardown() {
+	bridge.Disconnect()
+}
+
+func TestLocal(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	t.Run("Tests UploadFile method", func(t *testing.T) {
+		o, err := bridge.UploadFile(bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "images/a and ampersand.png",
+			Options: map[string]interface{}{
+				bifrost.OptMetadata: map[string]string{
+					"originalname": "aand.png",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload file: %v", err)
+		}
+		if o.URL != "https://files.example.com/bifrost/images/a%20and%20ampersand.png" || o.Preview != o.URL {
+			t.Errorf("Unexpected URL %s", o.URL)
+		}
+		if _, err := os.Stat(filepath.Join(root, "bifrost", "images", "a and ampersand.png")); err != nil {
+			t.Errorf("Expected the file in the bucket directory: %v", err)
+		}
+		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
+	})
+
+	t.Run("Tests UploadFile method with a handle", func(t *testing.T) {
+		var last bifrost.Progress
+		o, err := bridge.UploadFile(bifrost.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+			Options: map[string]interface{}{
+				bifrost.OptContentType: "text/plain",
+			},
+			Progress: func(p bifrost.Progress) {
+				last = p
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload file: %v", err)
+		}
+		if o.Size != 11 || last.Bytes != 11 || last.Total != 11 {
+			t.Errorf("Expected 11 bytes to be uploaded, got %d (progress %+v)", o.Size, last)
+		}
+	})
+
+	t.Run("Tests UploadFile method rejects names outside the bucket", func(t *testing.T) {
+		for _, name := range []string{"../escape.txt", "/abs.txt", ".bifrost/meta/x", "a/../../b"} {
+			if _, err := bridge.UploadFile(bifrost.File{Handle: strings.NewReader("x"), Filename: name}); err == nil {
+				t.Errorf("Expected upload as %q to fail", name)
+			}
+		}
+	})
+
+	t.Run("Tests UploadMultiFile method", func(t *testing.T) {
+		var last bifrost.BatchProgress
+		o, err := bridge.UploadMultiFile(bifrost.MultiFile{
+			Files: []bifrost.File{
+				{Path: "../shared/image/aand.png", Filename: "multi/a.png"},
+				{Path: "../shared/image/bifrost.webp", Filename: "multi/b.webp"},
+			},
+			Progress: func(p bifrost.BatchProgress) {
+				last = p
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload files: %v", err)
+		}
+		for _, file := range o {
+			if file.Error != nil {
+				t.Errorf("Failed to upload file %s: %v", file.Name, file.Error)
+			}
+		}
+		if last.Completed != 2 || last.Bytes != last.Total {
+			t.Errorf("Unexpected final batch progress %+v", last)
+		}
+	})
+
+	t.Run("Tests StatFile and Exists methods", func(t *testing.T) {
+		o, err := bridge.StatFile("images/a and ampersand.png")
+		if err != nil {
+			t.Fatalf("Failed to stat file: %v", err)
+		}
+		if o.ContentType != "image/png" || o.Metadata["originalname"] != "aand.png" || o.ETag == "" {
+			t.Errorf("Unexpected file attributes %+v", o)
+		}
+
+		o, err = bridge.StatFile("hello.txt")
+		if err != nil || o.ContentType != "text/plain" || o.ETag != "5eb63bbbe01eeed093cb22bb8f5acdc3" {
+			t.Errorf("Unexpected file attributes %+v: %v", o, err)
+		}
+
+		if _, err := bridge.StatFile("missing.txt"); !bifrost.IsNotFound(err) {
+			t.Errorf("Expected a not found error, got %v", err)
+		}
+		if ok, err := bridge.Exists("missing.txt"); ok || err != nil {
+			t.Errorf("Expected missing.txt not to exist, got %v %v", ok, err)
+		}
+	})
+
+	t.Run("Tests ListFiles method", func(t *testing.T) {
+		o, err := bridge.ListFiles(bifrost.ListOptions{Delimiter: "/"})
+		if err != nil {
+			t.Fatalf("Failed to list files: %v", err)
+		}
+		if len(o.Files) != 1 || o.Files[0].Name != "hello.txt" || strings.Join(o.Prefixes, ",") != "images/,multi/" {
+			t.Errorf("Unexpected listing %+v", o)
+		}
+
+		o, err = bridge.ListFiles(bifrost.ListOptions{Prefix: "multi/", PageSize: 1})
+		if err != nil || len(o.Files) != 1 || o.Files[0].Name != "multi/a.png" || o.NextToken == "" {
+			t.Fatalf("Unexpected first page %+v: %v", o, err)
+		}
+		o, err = bridge.ListFiles(bifrost.ListOptions{Prefix: "multi/", PageSize: 1, ContinuationToken: o.NextToken})
+		if err != nil || len(o.Files) != 1 || o.Files[0].Name != "multi/b.webp" || o.NextToken != "" {
+			t.Errorf("Unexpected second page %+v: %v", o, err)
+		}
+	})
+
+	t.Run("Tests OpenReader and DownloadFile methods", func(t *testing.T) {
+		rc, _, err := bridge.OpenReader("hello.txt")
+		if err != nil {
+			t.Fatalf("Failed to open file: %v", err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		if string(b) != "hello world" {
+			t.Errorf("Unexpected contents %q", b)
+		}
+
+		localPath := filepath.Join(t.TempDir(), "hello.txt")
+		if _, err := bridge.DownloadFile("hello.txt", localPath); err != nil {
+			t.Fatalf("Failed to download file: %v", err)
+		}
+		if b, _ := os.ReadFile(localPath); string(b) != "hello world" {
+			t.Errorf("Unexpected downloaded contents %q", b)
+		}
+	})
+
+	t.Run("Tests UploadJSON method", func(t *testing.T) {
+		uploader, ok := bridge.(bifrost.JSONUploader)
+		if !ok {
+			t.Fatalf("Expected the local bridge to implement bifrost.JSONUploader")
+		}
+		o, err := uploader.UploadJSON(context.Background(), bifrost.JSONFile{
+			Data:     map[string]interface{}{"name": "bifrost"},
+			Filename: "meta/1.json",
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload json: %v", err)
+		}
+		if o.Size != int64(len(`{"name":"bifrost"}`)) {
+			t.Errorf("Unexpected size %d", o.Size)
+		}
+
+		info, err := bridge.StatFile("meta/1.json")
+		if err != nil {
+			t.Fatalf("Failed to stat file: %v", err)
+		}
+		if info.ContentType != "application/json" {
+			t.Errorf("Expected content type application/json, got %q", info.ContentType)
+		}
+	})
+
+	t.Run("Tests UploadFileContext method with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := bridge.UploadFileContext(ctx, bifrost.File{
+			Handle:   strings.NewReader("cancelled"),
+			Filename: "cancelled.txt",
+		}); err == nil {
+			t.Errorf("Expected upload with a cancelled context to fail")
+		}
+		if ok, _ := bridge.Exists("cancelled.txt"); ok {
+			t.Errorf("Expected a cancelled upload to leave no file behind")
+		}
+	})
+
+	t.Run("Tests DeleteFile method", func(t *testing.T) {
+		if err := bridge.DeleteFile(bifrost.DeleteFile{Filename: "images/a and ampersand.png"}); err != nil {
+			t.Fatalf("Failed to delete file: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "bifrost", "images")); !os.IsNotExist(err) {
+			t.Errorf("Expected the emptied directory to be removed")
+		}
+		if err := bridge.DeleteFile(bifrost.DeleteFile{Filename: "images/a and ampersand.png"}); !bifrost.IsNotFound(err) {
+			t.Errorf("Expected a not found error, got %v", err)
+		}
+		if err := bridge.DeleteFile(bifrost.DeleteFile{Filename: "multi"}); !bifrost.IsNotFound(err) {
+			t.Errorf("Expected directories not to be deleted, got %v", err)
+		}
+	})
+}
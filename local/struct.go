@@ -0,0 +1,61 @@
+package local
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// LocalStorage is the local filesystem struct
+type LocalStorage struct {
+	// Provider is the name of the storage service to use.
+	Provider types.Provider
+	// Root is the absolute path to the directory files are stored under.
+	Root string
+	// DefaultBucket is the subdirectory of Root to use for storage.
+	DefaultBucket string
+	// BaseURL is the URL Root is served from, used to build the URL of stored files.
+	// file:// URLs are used when it is empty.
+	BaseURL string
+	// DefaultTimeout is the time-to-live for time-dependent local operations
+	DefaultTimeout int64
+	// PublicRead enables public read access to uploaded files.
+	PublicRead bool
+	// UseAsync enables asynchronous operations with go routines.
+	UseAsync bool
+	// MaxConcurrency is the maximum number of concurrent uploads when UseAsync is enabled.
+	MaxConcurrency int
+	// RetryPolicy configures how failed uploads are retried. Uploads are not retried when it is nil.
+	RetryPolicy *types.RetryPolicy
+	// EnableDebug enables debug logging.
+	EnableDebug bool
+}
+
+// sidecar is the metadata stored alongside each file.
+type sidecar struct {
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	ACL         string            `json:"acl,omitempty"`
+	ETag        string            `json:"etag,omitempty"`
+	Created     time.Time         `json:"created"`
+}
+
+// ctxReader is an io.Reader that stops reading once its context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+const (
+	// metaDir is the directory of each bucket holding the sidecar metadata files and in-flight uploads.
+	// It is hidden from listings and cannot be written to.
+	metaDir = ".bifrost"
+	// sidecarDir is the subdirectory of metaDir holding the sidecar metadata files.
+	sidecarDir = "meta"
+	// tempDir is the subdirectory of metaDir holding in-flight uploads, so that they are never visible under their final name.
+	tempDir = "tmp"
+	// sidecarExt is the extension of sidecar metadata files.
+	sidecarExt = ".json"
+)
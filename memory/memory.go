@@ -0,0 +1,800 @@
+// Bifrost interface for in-memory storage
+package memory
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/page"
+	"github.com/opensaucerer/bifrost/shared/pool"
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/stream"
+	"github.com/opensaucerer/bifrost/shared/types"
+	"github.com/opensaucerer/bifrost/shared/walk"
+)
+
+/*
+UploadFile uploads a file to memory and returns an error if one occurs.
+
+Note: for some providers, UploadFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (m *MemoryStorage) UploadFile(fileFace interface{}) (*types.UploadedFile, error) {
+	return m.UploadFileContext(context.Background(), fileFace)
+}
+
+// UploadFileContext is like UploadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (m *MemoryStorage) UploadFileContext(ctx context.Context, fileFace interface{}) (*types.UploadedFile, error) {
+	bFile, err := types.AsFile(fileFace)
+	if err != nil {
+		return nil, err
+	}
+	return m.Upload(ctx, bFile)
+}
+
+// Upload uploads a file to memory and returns an error if one occurs. It is the typed equivalent of UploadFileContext.
+// Uploads failing with a transient error are retried according to the bridge RetryPolicy.
+func (m *MemoryStorage) Upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	var uploadedFile *types.UploadedFile
+	err := retry.Upload(ctx, m.RetryPolicy, m.IsRetryable, bFile, func() error {
+		var err error
+		uploadedFile, err = m.upload(ctx, bFile)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uploadedFile, nil
+}
+
+/*
+UploadJSON marshals doc.Data and uploads it to memory as a JSON document with the application/json content type, unless
+another content type is set in doc.Options, and returns an error if one occurs.
+
+Note: UploadJSON requires doc.Filename.
+*/
+func (m *MemoryStorage) UploadJSON(ctx context.Context, doc types.JSONFile) (*types.UploadedFile, error) {
+	bFile, err := doc.File()
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+	return m.Upload(ctx, bFile)
+}
+
+// upload makes a single attempt at uploading a file to memory.
+func (m *MemoryStorage) upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	// validate struct
+	if err := bFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !m.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active memory storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if m.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(m.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	// every attempt counts as an upload for fault injection
+	m.mu.Lock()
+	m.uploads++
+	fault := m.failures[m.uploads]
+	m.mu.Unlock()
+
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+	if fault != nil {
+		return nil, &errors.BifrostError{
+			Err:       fault,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	if bFile.Path != "" {
+		// verify that file exists
+		if _, err := os.Stat(bFile.Path); os.IsNotExist(err) {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s", bFile.Path),
+				ErrorCode: errors.ErrBadRequest,
+			}
+		}
+		// open file
+		file, err := os.Open(bFile.Path)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+		// close file
+		defer file.Close()
+
+		bFile.Handle = file
+
+		// ensure filename
+		if bFile.Filename == "" {
+			bFile.Filename = filepath.Base(bFile.Path)
+		}
+	}
+
+	// work on a copy of the options so that the caller's map is never written to
+	bFile = bFile.Clone()
+
+	// if no ACL is set, check if m.PublicRead is true
+	if bFile.Options[config.OptACL] == nil && m.PublicRead {
+		// set public read permissions
+		bFile.Options[config.OptACL] = config.ACLPublicRead
+	}
+
+	obj := &Object{
+		Name:        bFile.Filename,
+		Bucket:      m.DefaultBucket,
+		ContentType: mime.TypeByExtension(path.Ext(bFile.Filename)),
+		Options:     bFile.Options,
+	}
+	// configure upload options
+	for k, v := range bFile.Options {
+		switch k {
+		// acl
+		case config.OptACL:
+			if v, ok := v.(string); ok && (v == config.ACLPublicRead || v == config.ACLPrivate) {
+				obj.ACL = v
+			}
+		// content type
+		case config.OptContentType:
+			if v, ok := v.(string); ok {
+				obj.ContentType = v
+			}
+		// metadata
+		case config.OptMetadata:
+			if v, ok := v.(map[string]string); ok {
+				obj.Metadata = copyMetadata(v)
+			}
+		}
+	}
+	if obj.ContentType == "" {
+		obj.ContentType = "application/octet-stream"
+	}
+
+	data, err := io.ReadAll(progress.Reader(bFile.Handle, bFile))
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	sum := md5.Sum(data)
+	obj.Data = data
+	obj.ETag = hex.EncodeToString(sum[:])
+
+	m.mu.Lock()
+	obj.Updated = time.Now().UTC()
+	obj.Created = obj.Updated
+	if old, ok := m.bucket(m.DefaultBucket)[obj.Name]; ok {
+		obj.Created = old.Created
+	}
+	m.bucket(m.DefaultBucket)[obj.Name] = obj
+	m.mu.Unlock()
+
+	return &types.UploadedFile{
+		Name:           obj.Name,
+		Bucket:         obj.Bucket,
+		Path:           bFile.Path,
+		Size:           int64(len(obj.Data)),
+		URL:            m.url(obj.Bucket, obj.Name),
+		Preview:        m.url(obj.Bucket, obj.Name),
+		ProviderObject: obj.copy(),
+	}, nil
+}
+
+/*
+UploadMultiFile uploads mutliple files to memory and returns an error if one occurs. If any of the uploads fail, the error is appended
+to the []UploadedFile.Error and also logged when debug is enabled while the rest of the uploads continue.
+
+Note: for some providers, UploadMultiFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (m *MemoryStorage) UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error) {
+	return m.UploadMultiFileContext(context.Background(), multiFace)
+}
+
+// UploadMultiFileContext is like UploadMultiFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies to each upload.
+func (m *MemoryStorage) UploadMultiFileContext(ctx context.Context, multiFace interface{}) ([]*types.UploadedFile, error) {
+	multiFile, err := types.AsMultiFile(multiFace)
+	if err != nil {
+		return nil, err
+	}
+	return m.UploadMulti(ctx, multiFile)
+}
+
+// UploadMulti uploads multiple files to memory. It is the typed equivalent of UploadMultiFileContext.
+func (m *MemoryStorage) UploadMulti(ctx context.Context, multiFile types.MultiFile) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := multiFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !m.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active memory storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+
+	uploadedFiles := make([]*types.UploadedFile, len(multiFile.Files))
+	batch := progress.NewBatch(multiFile)
+
+	// upload concurrently when UseAsync is true, writing each result at its file's index to keep the order of multiFile.Files
+	pool.Run(len(multiFile.Files), pool.Size(m.UseAsync, m.MaxConcurrency), func(i int) {
+		// merge global options into a copy of the file options so that shared maps are never written to
+		file := batch.Track(i, multiFile.Merge(i))
+
+		uploadedFile, err := m.Upload(ctx, file)
+		batch.Done(i, err)
+		if err != nil {
+			if m.EnableDebug {
+				// log failed file and continue
+				log.Printf("Upload for file at path %s failed with err: %s\n", file.Path, err.Error())
+			}
+			uploadedFiles[i] = &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path}
+			return
+		}
+		uploadedFiles[i] = uploadedFile
+	})
+
+	return uploadedFiles, nil
+}
+
+// Config returns the memory storage configuration.
+func (m *MemoryStorage) Config() *types.BridgeConfig {
+	return &types.BridgeConfig{
+		DefaultBucket:  m.DefaultBucket,
+		BaseURL:        m.BaseURL,
+		DefaultTimeout: m.DefaultTimeout,
+		EnableDebug:    m.EnableDebug,
+		Provider:       m.Provider,
+		PublicRead:     m.PublicRead,
+		UseAsync:       m.UseAsync,
+		MaxConcurrency: m.MaxConcurrency,
+		RetryPolicy:    m.RetryPolicy,
+	}
+}
+
+/*
+Disconnect closes the memory storage and returns an error if one occurs. Stored files stay available to the inspection helpers.
+
+Disconnect should only be called when the storage is no longer needed.
+*/
+func (m *MemoryStorage) Disconnect() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+// IsConnected returns true if the memory storage has not been disconnected.
+func (m *MemoryStorage) IsConnected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.closed
+}
+
+// Ping returns the error set with FailPing, or an error if the storage has been disconnected.
+func (m *MemoryStorage) Ping(ctx context.Context) error {
+	if !m.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active memory storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	if err := m.wait(ctx); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	fault := m.pingFailure
+	m.mu.Unlock()
+	if fault != nil {
+		return &errors.BifrostError{
+			Err:       fault,
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	return nil
+}
+
+/*
+UploadFolder uploads the files of a local folder, recursively, to memory and returns an error if one occurs.
+Files are stored under their path relative to the folder, joined to folder.Prefix. If any of the uploads fail, the error is
+appended to the []UploadedFile.Error while the rest of the uploads continue.
+
+Note: for some providers, UploadFolder requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (m *MemoryStorage) UploadFolder(foldFace interface{}) ([]*types.UploadedFile, error) {
+	return m.UploadFolderContext(context.Background(), foldFace)
+}
+
+// UploadFolderContext is like UploadFolder but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies to each upload.
+func (m *MemoryStorage) UploadFolderContext(ctx context.Context, foldFace interface{}) ([]*types.UploadedFile, error) {
+	folder, err := types.AsFolder(foldFace)
+	if err != nil {
+		return nil, err
+	}
+	return m.UploadDir(ctx, folder)
+}
+
+// UploadDir uploads the files of a local folder to memory. It is the typed equivalent of UploadFolderContext.
+func (m *MemoryStorage) UploadDir(ctx context.Context, folder types.Folder) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := folder.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	files, err := walk.Files(folder)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if len(files) == 0 {
+		return []*types.UploadedFile{}, nil
+	}
+
+	return m.UploadMulti(ctx, types.MultiFile{
+		Files:         files,
+		GlobalOptions: folder.Options,
+	})
+}
+
+/*
+DeleteFile deletes a file from memory and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
+
+Note: for some providers, DeleteFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (m *MemoryStorage) DeleteFile(fileFace interface{}) error {
+	return m.DeleteFileContext(context.Background(), fileFace)
+}
+
+// DeleteFileContext is like DeleteFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (m *MemoryStorage) DeleteFileContext(ctx context.Context, fileFace interface{}) error {
+	dFile, err := types.AsDeleteFile(fileFace)
+	if err != nil {
+		return err
+	}
+	return m.Delete(ctx, dFile)
+}
+
+// Delete deletes a file from memory. It is the typed equivalent of DeleteFileContext.
+func (m *MemoryStorage) Delete(ctx context.Context, dFile types.DeleteFile) error {
+	// validate struct
+	if err := dFile.Validate(); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+	// files are stored by name, so a CID alone does not identify one
+	if dFile.Filename == "" {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("file.Filename is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !m.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active memory storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if m.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(m.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+	if err := m.wait(ctx); err != nil {
+		return err
+	}
+
+	buckets := dFile.Buckets
+	if len(buckets) == 0 {
+		buckets = []string{m.DefaultBucket}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, bucket := range buckets {
+		if _, ok := m.bucket(bucket)[dFile.Filename]; !ok {
+			return &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s/%s", bucket, dFile.Filename),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		delete(m.bucket(bucket), dFile.Filename)
+	}
+	return nil
+}
+
+/*
+OpenReader opens a file stored in memory for reading and returns its attributes along with it.
+The caller must close the returned reader.
+
+Note: for some providers, OpenReader requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (m *MemoryStorage) OpenReader(name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	return m.OpenReaderContext(context.Background(), name)
+}
+
+// OpenReaderContext is like OpenReader but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (m *MemoryStorage) OpenReaderContext(ctx context.Context, name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	obj, err := m.stat(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return io.NopCloser(bytes.NewReader(obj.Data)), m.objectInfo(obj), nil
+}
+
+/*
+DownloadFile downloads a file stored in memory to localPath and returns its attributes.
+The file is written to a temporary file first and moved to localPath once complete.
+
+Note: for some providers, DownloadFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (m *MemoryStorage) DownloadFile(name, localPath string) (*types.ObjectInfo, error) {
+	return m.DownloadFileContext(context.Background(), name, localPath)
+}
+
+// DownloadFileContext is like DownloadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (m *MemoryStorage) DownloadFileContext(ctx context.Context, name, localPath string) (*types.ObjectInfo, error) {
+	if localPath == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("localPath is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	rc, info, err := m.OpenReaderContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if info.Size, err = stream.SaveFile(localPath, rc); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return info, nil
+}
+
+/*
+ListFiles lists the files stored in memory page by page and returns an error if one occurs.
+Pass the returned NextToken as opts.ContinuationToken to list the next page.
+
+Note: for some providers, ListFiles requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (m *MemoryStorage) ListFiles(opts types.ListOptions) (*types.ListResult, error) {
+	return m.ListFilesContext(context.Background(), opts)
+}
+
+// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (m *MemoryStorage) ListFilesContext(ctx context.Context, opts types.ListOptions) (*types.ListResult, error) {
+	// validate struct
+	if err := opts.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !m.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active memory storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if m.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(m.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	objects := m.bucket(m.DefaultBucket)
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+
+	files, prefixes, next := page.Names(names, opts)
+	result := &types.ListResult{
+		Files:     make([]*types.ObjectInfo, 0, len(files)),
+		Prefixes:  prefixes,
+		NextToken: next,
+	}
+	for _, name := range files {
+		result.Files = append(result.Files, m.objectInfo(objects[name].copy()))
+	}
+	return result, nil
+}
+
+/*
+StatFile returns the attributes of a file stored in memory and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
+
+Note: for some providers, StatFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (m *MemoryStorage) StatFile(name string) (*types.ObjectInfo, error) {
+	return m.StatFileContext(context.Background(), name)
+}
+
+// StatFileContext is like StatFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (m *MemoryStorage) StatFileContext(ctx context.Context, name string) (*types.ObjectInfo, error) {
+	obj, err := m.stat(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return m.objectInfo(obj), nil
+}
+
+// Exists returns true if a file is stored in memory and returns an error if the lookup fails.
+func (m *MemoryStorage) Exists(name string) (bool, error) {
+	return m.ExistsContext(context.Background(), name)
+}
+
+// ExistsContext is like Exists but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (m *MemoryStorage) ExistsContext(ctx context.Context, name string) (bool, error) {
+	if _, err := m.StatFileContext(ctx, name); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// IsRetryable returns true if err is a transient failure, such as an injected connection reset, that is worth retrying.
+func (m *MemoryStorage) IsRetryable(err error) bool {
+	return retry.IsTransient(err)
+}
+
+/*
+Objects returns a copy of the files stored in bucket, sorted by name. The default bucket is used when bucket is empty.
+
+Objects is meant for inspecting the storage in tests.
+*/
+func (m *MemoryStorage) Objects(bucket string) []Object {
+	if bucket == "" {
+		bucket = m.DefaultBucket
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	objects := make([]Object, 0, len(m.buckets[bucket]))
+	for _, obj := range m.buckets[bucket] {
+		objects = append(objects, *obj.copy())
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects
+}
+
+// Object returns a copy of the file stored as name in the default bucket, and false if there is none.
+func (m *MemoryStorage) Object(name string) (Object, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.buckets[m.DefaultBucket][name]
+	if !ok {
+		return Object{}, false
+	}
+	return *obj.copy(), true
+}
+
+/*
+FailUpload makes the n-th upload attempt, counting from 1 since the storage was created or last reset, fail with err.
+Retried attempts count as uploads of their own. Inject a transient error (e.g. syscall.ECONNRESET) to exercise retries.
+*/
+func (m *MemoryStorage) FailUpload(n int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failures == nil {
+		m.failures = make(map[int]error)
+	}
+	m.failures[n] = err
+}
+
+// FailPing makes every Ping fail with err until FailPing is called with a nil err, to exercise health checks.
+func (m *MemoryStorage) FailPing(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pingFailure = err
+}
+
+// Uploads returns the number of upload attempts made since the storage was created or last reset.
+func (m *MemoryStorage) Uploads() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.uploads
+}
+
+// SetLatency makes every operation wait for d before it runs, or until its context is done.
+func (m *MemoryStorage) SetLatency(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latency = d
+}
+
+// Reset removes every stored file, injected fault and latency, and resets the upload count.
+func (m *MemoryStorage) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.buckets = nil
+	m.failures = nil
+	m.pingFailure = nil
+	m.uploads = 0
+	m.latency = 0
+}
+
+// bucket returns the files of bucket, creating it if needed. It must be called with m.mu held.
+func (m *MemoryStorage) bucket(bucket string) map[string]*Object {
+	if m.buckets == nil {
+		m.buckets = make(map[string]map[string]*Object)
+	}
+	if m.buckets[bucket] == nil {
+		m.buckets[bucket] = make(map[string]*Object)
+	}
+	return m.buckets[bucket]
+}
+
+// wait sleeps for the configured latency, returning early with an error if ctx is done first.
+func (m *MemoryStorage) wait(ctx context.Context) error {
+	m.mu.Lock()
+	latency := m.latency
+	m.mu.Unlock()
+
+	if latency <= 0 {
+		if err := ctx.Err(); err != nil {
+			return &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+		return nil
+	}
+
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return &errors.BifrostError{
+			Err:       ctx.Err(),
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	case <-timer.C:
+		return nil
+	}
+}
+
+// stat returns a copy of the file stored as name in the default bucket.
+func (m *MemoryStorage) stat(ctx context.Context, name string) (*Object, error) {
+	if !m.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active memory storage"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if m.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(m.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	obj, ok := m.Object(name)
+	if !ok {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("file does not exist: %s", name),
+			ErrorCode: errors.ErrNotFound,
+		}
+	}
+	return &obj, nil
+}
+
+// objectInfo returns the attributes of obj.
+func (m *MemoryStorage) objectInfo(obj *Object) *types.ObjectInfo {
+	return &types.ObjectInfo{
+		Name:           obj.Name,
+		Bucket:         obj.Bucket,
+		Size:           int64(len(obj.Data)),
+		ContentType:    obj.ContentType,
+		Metadata:       obj.Metadata,
+		ETag:           obj.ETag,
+		Created:        obj.Created,
+		Updated:        obj.Updated,
+		URL:            m.url(obj.Bucket, obj.Name),
+		ProviderObject: obj,
+	}
+}
+
+// url returns the URL of the file stored as name in bucket.
+func (m *MemoryStorage) url(bucket, name string) string {
+	segments := strings.Split(strings.Trim(bucket+"/"+name, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	if m.BaseURL == "" {
+		return "memory://" + strings.Join(segments, "/")
+	}
+	return strings.TrimSuffix(m.BaseURL, "/") + "/" + strings.Join(segments, "/")
+}
+
+// copy returns a deep copy of obj, except for the option values.
+func (obj *Object) copy() *Object {
+	c := *obj
+	c.Data = append([]byte(nil), obj.Data...)
+	c.Metadata = copyMetadata(obj.Metadata)
+	if obj.Options != nil {
+		c.Options = make(map[string]interface{}, len(obj.Options))
+		for k, v := range obj.Options {
+			c.Options[k] = v
+		}
+	}
+	return &c
+}
+
+// copyMetadata returns a copy of metadata.
+func copyMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+	c := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		c[k] = v
+	}
+	return c
+}
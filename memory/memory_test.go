@@ -0,0 +1,162 @@
+package memory_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opensaucerer/bifrost"
+)
+
+var (
+	bridge bifrost.RainbowBridge
+	err    error
+)
+
+func setup(t *testing.T) {
+
+	bridge, err = bifrost.NewRainbowBridge(&bifrost.BridgeConfig{
+		DefaultBucket:  "bifrost",
+		DefaultTimeout: 10,
+		Provider:       bifrost.MemoryStorage,
+		EnableDebug:    true,
+		PublicRead:     true,
+	})
+	if err != nil {
+		t.Fatal(err.(bifrost.Error).Code(), err)
+	}
+
+	t.Logf("Connected to %s\n", bridge.Config().Provider)
+}
+
+func teardown() {
+	bridge.Disconnect()
+}
+
+func TestMemory(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	t.Run("Tests UploadFile method", func(t *testing.T) {
+		o, err := bridge.UploadFile(bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+			Options: map[string]interface{}{
+				bifrost.OptMetadata: map[string]string{
+					"originalname": "aand.png",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload file: %v", err)
+		}
+		if o.URL != "memory://bifrost/a_and_ampersand.png" {
+			t.Errorf("Unexpected URL %s", o.URL)
+		}
+		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
+	})
+
+	t.Run("Tests UploadMultiFile method", func(t *testing.T) {
+		o, err := bridge.UploadMultiFile(bifrost.MultiFile{
+			Files: []bifrost.File{
+				{Handle: strings.NewReader("hello"), Filename: "docs/hello.txt"},
+				{Handle: strings.NewReader("world"), Filename: "docs/world.txt"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload files: %v", err)
+		}
+		for _, file := range o {
+			if file.Error != nil {
+				t.Errorf("Failed to upload file %s: %v", file.Name, file.Error)
+			}
+		}
+	})
+
+	t.Run("Tests StatFile and Exists methods", func(t *testing.T) {
+		o, err := bridge.StatFile("a_and_ampersand.png")
+		if err != nil {
+			t.Fatalf("Failed to stat file: %v", err)
+		}
+		if o.ContentType != "image/png" || o.Metadata["originalname"] != "aand.png" || o.Size <= 0 {
+			t.Errorf("Unexpected file attributes %+v", o)
+		}
+		if ok, err := bridge.Exists("missing.txt"); ok || err != nil {
+			t.Errorf("Expected missing.txt not to exist, got %v %v", ok, err)
+		}
+	})
+
+	t.Run("Tests ListFiles method", func(t *testing.T) {
+		o, err := bridge.ListFiles(bifrost.ListOptions{Delimiter: "/"})
+		if err != nil {
+			t.Fatalf("Failed to list files: %v", err)
+		}
+		if len(o.Files) != 1 || o.Files[0].Name != "a_and_ampersand.png" || strings.Join(o.Prefixes, ",") != "docs/" {
+			t.Errorf("Unexpected listing %+v", o)
+		}
+	})
+
+	t.Run("Tests OpenReader and DownloadFile methods", func(t *testing.T) {
+		rc, _, err := bridge.OpenReader("docs/hello.txt")
+		if err != nil {
+			t.Fatalf("Failed to open file: %v", err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		if string(b) != "hello" {
+			t.Errorf("Unexpected contents %q", b)
+		}
+
+		localPath := filepath.Join(t.TempDir(), "world.txt")
+		if _, err := bridge.DownloadFile("docs/world.txt", localPath); err != nil {
+			t.Fatalf("Failed to download file: %v", err)
+		}
+		if b, _ := os.ReadFile(localPath); string(b) != "world" {
+			t.Errorf("Unexpected downloaded contents %q", b)
+		}
+	})
+
+	t.Run("Tests UploadFileContext method with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := bridge.UploadFileContext(ctx, bifrost.File{
+			Handle:   strings.NewReader("cancelled"),
+			Filename: "cancelled.txt",
+		}); err == nil {
+			t.Errorf("Expected upload with a cancelled context to fail")
+		}
+	})
+
+	t.Run("Tests DeleteFile method", func(t *testing.T) {
+		if err := bridge.DeleteFile(bifrost.DeleteFile{Filename: "docs/hello.txt"}); err != nil {
+			t.Fatalf("Failed to delete file: %v", err)
+		}
+		if err := bridge.DeleteFile(bifrost.DeleteFile{Filename: "docs/hello.txt"}); !bifrost.IsNotFound(err) {
+			t.Errorf("Expected a not found error, got %v", err)
+		}
+		if err := bridge.DeleteFile(bifrost.DeleteFile{CID: "QmHash"}); err == nil || err.(bifrost.Error).Code() != bifrost.ErrInvalidParameters {
+			t.Errorf("Expected %s error without a Filename, got %v", bifrost.ErrInvalidParameters, err)
+		}
+	})
+
+	t.Run("Tests UploadFolder method with an invalid pattern", func(t *testing.T) {
+		_, err := bridge.UploadFolder(bifrost.Folder{Path: "../shared/image", Include: []string{"[a-"}})
+		if err == nil || err.(bifrost.Error).Code() != bifrost.ErrInvalidParameters {
+			t.Errorf("Expected %s error, got %v", bifrost.ErrInvalidParameters, err)
+		}
+	})
+
+	t.Run("Tests Disconnect method", func(t *testing.T) {
+		bridge.Disconnect()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if _, err := bridge.StatFileContext(ctx, "a_and_ampersand.png"); err == nil {
+			t.Errorf("Expected operations on a disconnected bridge to fail")
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// MemoryStorage is the in-memory storage struct
+type MemoryStorage struct {
+	// Provider is the name of the storage service to use.
+	Provider types.Provider
+	// DefaultBucket is the bucket to use for storage.
+	DefaultBucket string
+	// BaseURL is used to build the URL of stored files. memory:// URLs are used when it is empty.
+	BaseURL string
+	// DefaultTimeout is the time-to-live for time-dependent memory operations
+	DefaultTimeout int64
+	// PublicRead enables public read access to uploaded files.
+	PublicRead bool
+	// UseAsync enables asynchronous operations with go routines.
+	UseAsync bool
+	// MaxConcurrency is the maximum number of concurrent uploads when UseAsync is enabled.
+	MaxConcurrency int
+	// RetryPolicy configures how failed uploads are retried. Uploads are not retried when it is nil.
+	RetryPolicy *types.RetryPolicy
+	// EnableDebug enables debug logging.
+	EnableDebug bool
+
+	mu          sync.Mutex
+	closed      bool
+	buckets     map[string]map[string]*Object
+	uploads     int
+	failures    map[int]error
+	pingFailure error
+	latency     time.Duration
+}
+
+// Object is a file stored in memory.
+type Object struct {
+	// Name is the name the file is stored as.
+	Name string
+	// Bucket is the bucket the file is stored in.
+	Bucket string
+	// Data is the content of the file.
+	Data []byte
+	// ContentType is the MIME type of the file.
+	ContentType string
+	// Metadata is the user metadata stored along with the file.
+	Metadata map[string]string
+	// ACL is the access control the file was stored with (e.g. bifrost.ACLPublicRead), if any.
+	ACL string
+	// Options are the upload options the file was stored with, after global options were merged in.
+	Options map[string]interface{}
+	// ETag is the MD5 checksum of Data in hex.
+	ETag string
+	// Created is the time the file was first stored.
+	Created time.Time
+	// Updated is the time the file was last stored.
+	Updated time.Time
+}
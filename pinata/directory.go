@@ -0,0 +1,183 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/stream"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+/*
+uploadDirectory pins files as a single IPFS directory in one multipart request and returns an entry for every file,
+in order, with the CID of the directory and the gateway URL the file resolves at.
+
+When dir is set every file is sent as dir/Filename, which Pinata pins as a directory named dir whose CID is returned.
+Otherwise options must enable wrapWithDirectory for Pinata to wrap the files in a directory. Only options, and not the
+options of each file, are sent since Pinata pins the whole directory at once.
+*/
+func (p *PinataCloud) uploadDirectory(ctx context.Context, dir string, multiFile types.MultiFile) ([]*types.UploadedFile, error) {
+	data, err := formData(multiFile.GlobalOptions, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := progress.NewBatch(multiFile)
+	param := types.Param{
+		Files: make([]types.ParamFile, len(multiFile.Files)),
+		Data:  data,
+	}
+	// streams cannot be sent again, so the request is only retried when every file is read from disk
+	rewindable := true
+	// sizes are taken before the request since it consumes the streams
+	sizes := make([]int64, len(multiFile.Files))
+	for i, file := range multiFile.Files {
+		file = batch.Track(i, file)
+		pf := types.ParamFile{
+			Key:  "file",
+			Name: path.Join(dir, filepath.ToSlash(file.Filename)),
+			Path: file.Path,
+		}
+		if file.Path == "" {
+			rewindable = false
+			if size := stream.Size(file.Handle); size > 0 {
+				sizes[i] = size
+			}
+			pf.Handle = progress.Reader(file.Handle, file)
+		} else if info, err := os.Stat(file.Path); err == nil {
+			sizes[i] = info.Size()
+		}
+		if file.Filename == "" {
+			pf.Name = path.Join(dir, filepath.Base(file.Path))
+		}
+		param.Files[i] = pf
+	}
+
+	var res []byte
+	send := func(int) error {
+		var err error
+		res, err = p.Client.PostForm(ctx, config.URLPinataPinFile, param)
+		return err
+	}
+	if rewindable {
+		err = retry.Do(ctx, p.RetryPolicy, p.IsRetryable, send)
+	} else {
+		err = send(1)
+	}
+	for i := range multiFile.Files {
+		batch.Done(i, err)
+	}
+	if err != nil {
+		if p.EnableDebug {
+			log.Printf("Upload of directory %s failed with err: %s\n", dir, err.Error())
+		}
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	var obj types.PinataPinFileResponse
+	if err := json.Unmarshal(res, &obj); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("failed to unmarshal response: %s", err.Error()),
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	if obj.Error != "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("failed to upload directory: %s", obj.Error),
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	uploadedFiles := make([]*types.UploadedFile, len(multiFile.Files))
+	for i, file := range multiFile.Files {
+		// the files resolve under the root CID by their name, behind dir if Pinata also wrapped it
+		name := strings.TrimPrefix(param.Files[i].Name, dir+"/")
+		if dir != "" && wrapsDirectory(multiFile.GlobalOptions) {
+			name = param.Files[i].Name
+		}
+		uploadedFiles[i] = &types.UploadedFile{
+			Name:           name,
+			Path:           file.Path,
+			Size:           sizes[i],
+			CID:            obj.IpfsHash,
+			Preview:        gatewayURL(obj.IpfsHash, name),
+			URL:            gatewayURL(obj.IpfsHash, name),
+			ProviderObject: obj,
+		}
+	}
+	return uploadedFiles, nil
+}
+
+// formData encodes the pinataOptions and pinataMetadata options as multipart form fields. The pin is named after name,
+// when set, unless pinataMetadata names it.
+func formData(options map[string]interface{}, name string) ([]types.ParamData, error) {
+	data := []types.ParamData{}
+	if v, ok := options[config.OptPinata].(map[string]interface{}); ok {
+		opt, err := json.Marshal(v)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("failed to marshal pinata options: %s", err.Error()),
+				ErrorCode: errors.ErrBadRequest,
+			}
+		}
+		data = append(data, types.ParamData{
+			Key:   config.OptPinata,
+			Value: string(opt),
+		})
+	}
+
+	metadata := map[string]interface{}{}
+	if v, ok := options[config.OptPinataMetadata].(map[string]interface{}); ok {
+		for k, v := range v {
+			metadata[k] = v
+		}
+	}
+	if _, ok := metadata["name"]; !ok && name != "" {
+		metadata["name"] = name
+	}
+	if len(metadata) > 0 {
+		m, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("failed to marshal pinata metadata: %s", err.Error()),
+				ErrorCode: errors.ErrBadRequest,
+			}
+		}
+		data = append(data, types.ParamData{
+			Key:   config.OptPinataMetadata,
+			Value: string(m),
+		})
+	}
+	return data, nil
+}
+
+// wrapsDirectory returns true if the pinataOptions in options enable wrapWithDirectory.
+func wrapsDirectory(options map[string]interface{}) bool {
+	opt, _ := options[config.OptPinata].(map[string]interface{})
+	wrap, _ := opt[pinataWrapWithDirectory].(bool)
+	return wrap
+}
+
+// gatewayURL returns the public gateway URL of the file at name, relative to the directory with the given CID.
+func gatewayURL(cid, name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf(config.URLPinataGateway, cid+"/"+strings.Join(segments, "/"))
+}
@@ -0,0 +1,138 @@
+package pinata
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// fakeDirectory records the parts of pinFileToIPFS requests.
+type fakeDirectory struct {
+	mu       sync.Mutex
+	requests int
+	files    map[string]string
+	options  map[string]interface{}
+	metadata map[string]interface{}
+}
+
+func (f *fakeDirectory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	reader, err := r.MultipartReader()
+	if r.URL.Path != "/pinning/pinFileToIPFS" || err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.requests++
+	f.files = map[string]string{}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		b, _ := io.ReadAll(part)
+		switch part.FormName() {
+		case "file":
+			// FileName drops the directories of the file name
+			_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+			f.files[params["filename"]] = string(b)
+		case config.OptPinata:
+			json.Unmarshal(b, &f.options)
+		case config.OptPinataMetadata:
+			json.Unmarshal(b, &f.metadata)
+		}
+	}
+	w.Write([]byte(`{"IpfsHash":"QmRoot","PinSize":42,"Timestamp":"2023-01-01T00:00:00Z"}`))
+}
+
+func TestUploadDirectory(t *testing.T) {
+	t.Run("Tests UploadFolder method pins a single directory", func(t *testing.T) {
+		fake := &fakeDirectory{}
+		p := newFakePinata(t, fake.ServeHTTP)
+
+		root := filepath.Join(t.TempDir(), "site")
+		os.MkdirAll(filepath.Join(root, "img"), 0o755)
+		os.WriteFile(filepath.Join(root, "index.html"), []byte("<html></html>"), 0o644)
+		os.WriteFile(filepath.Join(root, "img", "logo 1.png"), []byte("png"), 0o644)
+
+		o, err := p.UploadFolder(types.Folder{Path: root})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fake.requests != 1 {
+			t.Fatalf("Expected a single request, got %d", fake.requests)
+		}
+		if fake.files["site/index.html"] != "<html></html>" || fake.files["site/img/logo 1.png"] != "png" {
+			t.Errorf("Expected the files to be sent with their relative paths, got %v", fake.files)
+		}
+		if fake.metadata["name"] != "site" {
+			t.Errorf("Expected the pin to be named after the folder, got metadata %v", fake.metadata)
+		}
+
+		urls := map[string]string{}
+		for _, file := range o {
+			if file.CID != "QmRoot" {
+				t.Errorf("Expected every file to carry the root CID, got %+v", file)
+			}
+			urls[file.Name] = file.URL
+		}
+		if urls["index.html"] != "https://gateway.pinata.cloud/ipfs/QmRoot/index.html" ||
+			urls["img/logo 1.png"] != "https://gateway.pinata.cloud/ipfs/QmRoot/img/logo%201.png" {
+			t.Errorf("Unexpected gateway paths %v", urls)
+		}
+	})
+
+	t.Run("Tests UploadMultiFile method with wrapWithDirectory", func(t *testing.T) {
+		fake := &fakeDirectory{}
+		p := newFakePinata(t, fake.ServeHTTP)
+
+		o, err := p.UploadMultiFile(types.MultiFile{
+			Files: []types.File{
+				{Handle: strings.NewReader(`{"name":"#1"}`), Filename: "1.json"},
+				{Handle: strings.NewReader(`{"name":"#2"}`), Filename: "2.json"},
+			},
+			GlobalOptions: map[string]interface{}{
+				config.OptPinata: map[string]interface{}{"wrapWithDirectory": true},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fake.requests != 1 || len(fake.files) != 2 || fake.files["1.json"] != `{"name":"#1"}` {
+			t.Errorf("Expected both files in a single request, got %d requests with %v", fake.requests, fake.files)
+		}
+		if fake.options["wrapWithDirectory"] != true {
+			t.Errorf("Expected wrapWithDirectory to be sent, got %v", fake.options)
+		}
+		if len(o) != 2 || o[1].CID != "QmRoot" || o[1].URL != "https://gateway.pinata.cloud/ipfs/QmRoot/2.json" || o[1].Size != 13 {
+			t.Errorf("Unexpected uploaded files %+v", o)
+		}
+	})
+
+	t.Run("Tests UploadMultiFile method without wrapWithDirectory", func(t *testing.T) {
+		fake := &fakeDirectory{}
+		p := newFakePinata(t, fake.ServeHTTP)
+
+		if _, err := p.UploadMultiFile(types.MultiFile{
+			Files: []types.File{
+				{Handle: strings.NewReader("1"), Filename: "1.txt"},
+				{Handle: strings.NewReader("2"), Filename: "2.txt"},
+			},
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fake.requests != 2 {
+			t.Errorf("Expected a request per file, got %d", fake.requests)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package pinata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/opensaucerer/bifrost/shared/request"
+)
+
+// rewrite sends every request to the test server instead of the Pinata API.
+type rewrite struct {
+	target *url.URL
+}
+
+func (r rewrite) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newFakePinata returns a Pinata bridge whose requests are served by handler.
+func newFakePinata(t *testing.T, handler http.HandlerFunc) *PinataCloud {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	target, _ := url.Parse(server.URL)
+
+	client := request.NewClient(server.URL, "jwt", 10)
+	client.Http.Transport = rewrite{target: target}
+	return &PinataCloud{
+		Provider:       "Pinata Cloud",
+		DefaultTimeout: 10,
+		PinataJWT:      "jwt",
+		Client:         client,
+	}
+}
@@ -0,0 +1,56 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+func TestUploadJSON(t *testing.T) {
+	var body map[string]interface{}
+	p := newFakePinata(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pinning/pinJSONToIPFS" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"IpfsHash":"QmHash","PinSize":42,"Timestamp":"2023-01-01T00:00:00Z"}`))
+	})
+
+	t.Run("Tests UploadJSON method", func(t *testing.T) {
+		o, err := p.UploadJSON(context.Background(), types.JSONFile{
+			Data:     map[string]interface{}{"name": "bifrost"},
+			Filename: "1.json",
+			Options: map[string]interface{}{
+				config.OptPinata:         map[string]interface{}{"cidVersion": 1},
+				config.OptPinataMetadata: map[string]interface{}{"keyvalues": map[string]interface{}{"kind": "nft"}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if o.CID != "QmHash" || o.Size != 42 || o.URL != "https://gateway.pinata.cloud/ipfs/QmHash" {
+			t.Errorf("Unexpected uploaded file %+v", o)
+		}
+
+		content, _ := body[pinataContent].(map[string]interface{})
+		options, _ := body[config.OptPinata].(map[string]interface{})
+		metadata, _ := body[config.OptPinataMetadata].(map[string]interface{})
+		if content["name"] != "bifrost" || options["cidVersion"] != float64(1) {
+			t.Errorf("Unexpected request body %v", body)
+		}
+		if metadata["name"] != "1.json" || metadata["keyvalues"] == nil {
+			t.Errorf("Expected the filename to name the pin, got metadata %v", metadata)
+		}
+	})
+
+	t.Run("Tests UploadJSON method without data", func(t *testing.T) {
+		if _, err := p.UploadJSON(context.Background(), types.JSONFile{Filename: "1.json"}); err == nil {
+			t.Errorf("Expected a document without data to be rejected")
+		}
+	})
+}
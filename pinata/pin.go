@@ -0,0 +1,212 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// PinJob is a handle on a CID queued to be pinned by Pinata. Pinata searches the IPFS network for the content in the
+// background, so the job has to be polled until its status is pinned or failed. A PinJob is not safe for concurrent use.
+type PinJob struct {
+	// ID is the identifier Pinata gave the job.
+	ID string
+	// CID is the CID being pinned.
+	CID string
+	// Name is the name of the pin, as set in the metadata.
+	Name string
+	// Status is the status of the job as of the last poll.
+	Status types.PinStatus
+
+	p *PinataCloud
+}
+
+/*
+PinByCID asks Pinata to pin content already on the IPFS network by its CID and returns a job to track the pin.
+metadata is sent as the pinataMetadata of the pin (e.g. its name and keyvalues) and may be nil.
+
+Pinata pins the CID asynchronously: use PinJob.Poll to refresh the status of the job or PinJob.Wait to block until
+the CID is pinned or the job fails.
+*/
+func (p *PinataCloud) PinByCID(cid string, metadata map[string]interface{}) (*PinJob, error) {
+	return p.PinByCIDContext(context.Background(), cid, metadata)
+}
+
+// PinByCIDContext is like PinByCID but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (p *PinataCloud) PinByCIDContext(ctx context.Context, cid string, metadata map[string]interface{}) (*PinJob, error) {
+	if cid == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("cid is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !p.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Pinata client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if p.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	body := map[string]interface{}{
+		pinataHashToPin: cid,
+	}
+	if len(metadata) > 0 {
+		body[config.OptPinataMetadata] = metadata
+	}
+
+	var res []byte
+	err := retry.Do(ctx, p.RetryPolicy, p.IsRetryable, func(int) error {
+		var err error
+		res, err = p.Client.PostJSON(ctx, config.URLPinataPinCID, body)
+		return err
+	})
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	var obj types.PinataPinByHashResponse
+	if err := json.Unmarshal(res, &obj); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("failed to unmarshal response: %s", err.Error()),
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	job := &PinJob{
+		ID:     obj.ID,
+		CID:    cid,
+		Name:   obj.Name,
+		Status: types.PinStatus(obj.Status),
+		p:      p,
+	}
+	if obj.IpfsHash != "" {
+		job.CID = obj.IpfsHash
+	}
+	return job, nil
+}
+
+/*
+Poll refreshes the status of the job and returns it along with an error if the lookup fails.
+DefaultTimeout, when set, applies on top of ctx.
+
+Pinata drops a job from its queue once the CID is pinned, so a job that is no longer queued is reported as pinned if
+the CID is pinned by the account and otherwise keeps its last status.
+*/
+func (j *PinJob) Poll(ctx context.Context) (types.PinStatus, error) {
+	p := j.p
+	if p == nil || !p.IsConnected() {
+		return j.Status, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Pinata client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if p.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	q := url.Values{}
+	q.Set("ipfs_pin_hash", j.CID)
+
+	res, err := p.Client.Get(ctx, config.URLPinataPinJobs+"?"+q.Encode())
+	if err != nil {
+		return j.Status, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	var obj types.PinataPinJobsResponse
+	if err := json.Unmarshal(res, &obj); err != nil {
+		return j.Status, &errors.BifrostError{
+			Err:       fmt.Errorf("failed to unmarshal response: %s", err.Error()),
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	for _, row := range obj.Rows {
+		if (j.ID != "" && row.ID == j.ID) || (j.ID == "" && row.IpfsPinHash == j.CID) {
+			j.Status = types.PinStatus(row.Status)
+			return j.Status, nil
+		}
+	}
+
+	// the job has left the queue, which it does once the CID is pinned
+	if _, err := p.StatFileContext(ctx, j.CID); err != nil {
+		if errors.IsNotFound(err) {
+			return j.Status, nil
+		}
+		return j.Status, err
+	}
+	j.Status = types.PinStatusPinned
+	return j.Status, nil
+}
+
+/*
+Wait polls the job every PinPollInterval until the CID is pinned or the job fails and returns an error if the job
+fails, ctx is done or the wait outlasts DefaultTimeout.
+*/
+func (j *PinJob) Wait(ctx context.Context) error {
+	p := j.p
+	if p == nil {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active Pinata client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// the timeout covers the whole wait rather than each poll
+	var cancel context.CancelFunc
+	if p.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	interval := p.PinPollInterval
+	if interval <= 0 {
+		interval = config.DefaultPinPollInterval
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for !j.Status.Done() {
+		select {
+		case <-ctx.Done():
+			return &errors.BifrostError{
+				Err:       fmt.Errorf("pin of %s is still %s: %w", j.CID, j.Status, ctx.Err()),
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		case <-timer.C:
+		}
+		if _, err := j.Poll(ctx); err != nil {
+			return err
+		}
+		timer.Reset(interval)
+	}
+
+	if j.Status.Failed() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("failed to pin %s: %s", j.CID, j.Status),
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return nil
+}
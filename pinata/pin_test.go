@@ -0,0 +1,156 @@
+package pinata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// fakePinJobs serves pinByHash and the pin job queue, moving a queued job through statuses one poll at a time. A job
+// leaves the queue and is listed as pinned once it has been retrieving, and otherwise stays in its last status.
+type fakePinJobs struct {
+	mu       sync.Mutex
+	body     map[string]interface{}
+	statuses []string
+	polls    int
+}
+
+func (f *fakePinJobs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/pinning/pinByHash":
+		json.NewDecoder(r.Body).Decode(&f.body)
+		w.Write([]byte(`{"id":"job","ipfsHash":"QmHash","status":"prechecking","name":"hello.txt"}`))
+	case "/pinning/pinJobs":
+		f.polls++
+		if r.URL.Query().Get("ipfs_pin_hash") != "QmHash" || len(f.statuses) == 0 {
+			w.Write([]byte(`{"count":0,"rows":[]}`))
+			return
+		}
+		status := f.statuses[0]
+		if len(f.statuses) > 1 {
+			f.statuses = f.statuses[1:]
+		} else if status == "retrieving" {
+			f.statuses = nil
+		}
+		json.NewEncoder(w).Encode(types.PinataPinJobsResponse{
+			Count: 1,
+			Rows:  []types.PinataPinJob{{ID: "job", IpfsPinHash: "QmHash", Status: status}},
+		})
+	case "/data/pinList":
+		if len(f.statuses) > 0 {
+			w.Write([]byte(`{"count":0,"rows":[]}`))
+			return
+		}
+		w.Write([]byte(`{"count":1,"rows":[{"id":"pin","ipfs_pin_hash":"QmHash","size":11}]}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakePinJobs(t *testing.T, statuses ...string) (*PinataCloud, *fakePinJobs) {
+	fake := &fakePinJobs{statuses: statuses}
+	p := newFakePinata(t, fake.ServeHTTP)
+	p.PinPollInterval = time.Millisecond
+	return p, fake
+}
+
+func TestPinByCID(t *testing.T) {
+	t.Run("Tests PinByCID method", func(t *testing.T) {
+		p, fake := newFakePinJobs(t, "searching", "retrieving")
+
+		job, err := p.PinByCID("QmHash", map[string]interface{}{"name": "hello.txt"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if job.ID != "job" || job.CID != "QmHash" || job.Name != "hello.txt" || job.Status != types.PinStatusPrechecking {
+			t.Errorf("Unexpected pin job %+v", job)
+		}
+		metadata, _ := fake.body[config.OptPinataMetadata].(map[string]interface{})
+		if fake.body[pinataHashToPin] != "QmHash" || metadata["name"] != "hello.txt" {
+			t.Errorf("Unexpected request body %v", fake.body)
+		}
+
+		status, err := job.Poll(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if status != types.PinStatusSearching || status.Done() {
+			t.Errorf("Expected the job to be searching, got %s", status)
+		}
+
+		if status, err := job.Poll(context.Background()); err != nil || status != types.PinStatusRetrieving {
+			t.Errorf("Expected the job to be retrieving, got %s: %v", status, err)
+		}
+		// the job has left the queue and the CID is pinned
+		if status, err := job.Poll(context.Background()); err != nil || !status.Pinned() {
+			t.Errorf("Expected the job to be pinned, got %s: %v", status, err)
+		}
+	})
+
+	t.Run("Tests Wait method until the CID is pinned", func(t *testing.T) {
+		p, fake := newFakePinJobs(t, "searching", "searching", "retrieving")
+
+		job, err := p.PinByCID("QmHash", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, ok := fake.body[config.OptPinataMetadata]; ok {
+			t.Errorf("Expected no metadata to be sent, got %v", fake.body)
+		}
+		if err := job.Wait(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if job.Status != types.PinStatusPinned || fake.polls != 4 {
+			t.Errorf("Expected the job to be pinned after 4 polls, got %s after %d", job.Status, fake.polls)
+		}
+	})
+
+	t.Run("Tests Wait method when the pin fails", func(t *testing.T) {
+		p, _ := newFakePinJobs(t, "searching", "expired")
+
+		job, err := p.PinByCID("QmHash", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		err = job.Wait(context.Background())
+		if err == nil || !job.Status.Failed() {
+			t.Fatalf("Expected the pin to fail, got %s: %v", job.Status, err)
+		}
+		if code := err.(*errors.BifrostError).Code(); code != errors.ErrFileOperationFailed {
+			t.Errorf("Expected %s error, got %s", errors.ErrFileOperationFailed, code)
+		}
+	})
+
+	t.Run("Tests Wait method times out after DefaultTimeout", func(t *testing.T) {
+		p, _ := newFakePinJobs(t, "searching")
+		p.DefaultTimeout = 1
+		p.PinPollInterval = 400 * time.Millisecond
+		// the job never leaves the queue
+		job := &PinJob{ID: "job", CID: "QmHash", Status: types.PinStatusSearching, p: p}
+
+		start := time.Now()
+		if err := job.Wait(context.Background()); err == nil {
+			t.Fatalf("Expected the wait to time out")
+		}
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Errorf("Expected the wait to stop after DefaultTimeout, took %s", elapsed)
+		}
+	})
+
+	t.Run("Tests PinByCID method without a CID", func(t *testing.T) {
+		p, _ := newFakePinJobs(t)
+		if _, err := p.PinByCID("", nil); err == nil {
+			t.Errorf("Expected an empty CID to be rejected")
+		}
+	})
+}
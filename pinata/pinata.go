@@ -2,33 +2,67 @@
 package pinata
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/opensaucerer/bifrost/cid"
 	"github.com/opensaucerer/bifrost/shared/config"
 	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/pool"
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/request"
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/stream"
 	"github.com/opensaucerer/bifrost/shared/types"
+	"github.com/opensaucerer/bifrost/shared/walk"
 )
 
 /*
 UploadFile uploads a file to Pinata and returns an error if one occurs.
+
+When VerifyCID is enabled, the IpfsHash returned by Pinata is checked against the CID computed from the file for the
+cidVersion of its pinataOptions, and a mismatch is returned as an error with the code ErrIntegrity.
 */
 func (p *PinataCloud) UploadFile(fileFace interface{}) (*types.UploadedFile, error) {
+	return p.UploadFileContext(context.Background(), fileFace)
+}
 
-	// assert that the fileFace is of type bifrost.File
-	bFile, ok := fileFace.(types.File)
-	if !ok {
-		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("argument must be of type bifrost.File"),
-			ErrorCode: errors.ErrBadRequest,
-		}
+// UploadFileContext is like UploadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (p *PinataCloud) UploadFileContext(ctx context.Context, fileFace interface{}) (*types.UploadedFile, error) {
+	bFile, err := types.AsFile(fileFace)
+	if err != nil {
+		return nil, err
+	}
+	return p.Upload(ctx, bFile)
+}
+
+// Upload uploads a file to Pinata and returns an error if one occurs. It is the typed equivalent of UploadFileContext.
+// Uploads failing with a transient error are retried according to the bridge RetryPolicy.
+func (p *PinataCloud) Upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	var uploadedFile *types.UploadedFile
+	err := retry.Upload(ctx, p.RetryPolicy, p.IsRetryable, bFile, func() error {
+		var err error
+		uploadedFile, err = p.upload(ctx, bFile)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return uploadedFile, nil
+}
 
+// upload makes a single attempt at uploading a file to Pinata.
+func (p *PinataCloud) upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
 	// validate struct
 	if err := bFile.Validate(); err != nil {
 		return nil, &errors.BifrostError{
@@ -52,6 +86,18 @@ func (p *PinataCloud) UploadFile(fileFace interface{}) (*types.UploadedFile, err
 				ErrorCode: errors.ErrBadRequest,
 			}
 		}
+		// open file
+		file, err := os.Open(bFile.Path)
+		if err != nil {
+			return nil, &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+		// close file
+		defer file.Close()
+
+		bFile.Handle = file
 
 		// build the request params
 		if bFile.Filename == "" {
@@ -59,56 +105,156 @@ func (p *PinataCloud) UploadFile(fileFace interface{}) (*types.UploadedFile, err
 		}
 	}
 
+	// compute the CID that Pinata should return, unless the file is wrapped in a directory
+	var builder *cid.Builder
+	if p.VerifyCID && !wrapsDirectory(bFile.Options) {
+		var err error
+		if builder, err = p.expectCID(&bFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// configure upload options
+	data, err := formData(bFile.Options, "")
+	if err != nil {
+		return nil, err
+	}
+
 	var param types.Param = types.Param{
 		Files: []types.ParamFile{
 			{
-				Path:   bFile.Path,
-				Handle: bFile.Handle,
+				// report progress as the multipart body is streamed
+				Handle: progress.Reader(bFile.Handle, bFile),
 				Key:    "file",
 				Name:   bFile.Filename,
 			},
 		},
-		Data: []types.ParamData{},
+		Data: data,
 	}
 
-	// configure upload options
-	for k, v := range bFile.Options {
-		switch k {
-		// pinataOptions
-		case config.OptPinata:
-			if v, ok := v.(map[string]interface{}); ok {
-				opt, err := json.Marshal(v)
-				if err != nil {
-					return nil, &errors.BifrostError{
-						Err:       fmt.Errorf("failed to marshal pinata options: %s", err.Error()),
-						ErrorCode: errors.ErrBadRequest,
-					}
-				}
-				param.Data = append(param.Data, types.ParamData{
-					Key:   config.OptPinata,
-					Value: string(opt),
-				})
-			}
+	res, err := p.Client.PostForm(ctx, config.URLPinataPinFile, param)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	var obj types.PinataPinFileResponse
+	if err := json.Unmarshal(res, &obj); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("failed to unmarshal response: %s", err.Error()),
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	if obj.Error != "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("failed to upload file: %s", obj.Error),
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
 
-		// pinataMetadata
-		case config.PinataCloud + strings.ToUpper(config.OptMetadata[:1]) + config.OptMetadata[1:]:
-			if v, ok := v.(map[string]interface{}); ok {
-				m, err := json.Marshal(v)
-				if err != nil {
-					return nil, &errors.BifrostError{
-						Err:       fmt.Errorf("failed to marshal pinata metadata: %s", err.Error()),
-						ErrorCode: errors.ErrBadRequest,
-					}
-				}
-				param.Data = append(param.Data, types.ParamData{
-					Key:   config.PinataCloud + strings.ToUpper(config.OptMetadata[:1]) + config.OptMetadata[1:],
-					Value: string(m),
-				})
+	if builder != nil {
+		if expected := builder.Sum(); obj.IpfsHash != expected {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("pinata returned CID %s for %s instead of %s", obj.IpfsHash, bFile.Filename, expected),
+				ErrorCode: errors.ErrIntegrity,
 			}
 		}
 	}
 
-	res, err := p.Client.PostForm(config.URLPinataPinFile, param)
+	return &types.UploadedFile{
+		Size:           obj.PinSize,
+		CID:            obj.IpfsHash,
+		Preview:        fmt.Sprintf(config.URLPinataGateway, obj.IpfsHash),
+		ProviderObject: obj,
+		Name:           filepath.Base(bFile.Path),
+		Path:           bFile.Path,
+		URL:            fmt.Sprintf(config.URLPinataGateway, obj.IpfsHash),
+	}, nil
+}
+
+/*
+expectCID returns a builder computing the CID of bFile as it is uploaded, for the cidVersion of its pinataOptions.
+Seekable handles are read ahead and rewound while other handles are replaced with a reader that feeds the builder.
+*/
+func (p *PinataCloud) expectCID(bFile *types.File) (*cid.Builder, error) {
+	builder, err := cid.NewBuilder(cid.VersionOf(bFile.Options))
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	s, ok := bFile.Handle.(io.Seeker)
+	if !ok {
+		bFile.Handle = io.TeeReader(bFile.Handle, builder)
+		return builder, nil
+	}
+	start, err := s.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = io.Copy(builder, bFile.Handle)
+	}
+	if err == nil {
+		_, err = s.Seek(start, io.SeekStart)
+	}
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return builder, nil
+}
+
+/*
+UploadJSON pins doc.Data to IPFS as a JSON document and returns an error if one occurs.
+The pinataOptions and pinataMetadata options are sent along with the document, and doc.Filename is used as the pin name
+unless pinataMetadata sets one. Uploads failing with a transient error are retried according to the bridge RetryPolicy.
+*/
+func (p *PinataCloud) UploadJSON(ctx context.Context, doc types.JSONFile) (*types.UploadedFile, error) {
+	// validate struct
+	if err := doc.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !p.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Pinata client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+
+	body := map[string]interface{}{
+		pinataContent: doc.Data,
+	}
+	if v, ok := doc.Options[config.OptPinata].(map[string]interface{}); ok {
+		body[config.OptPinata] = v
+	}
+	metadata := map[string]interface{}{}
+	if v, ok := doc.Options[config.OptPinataMetadata].(map[string]interface{}); ok {
+		for k, v := range v {
+			metadata[k] = v
+		}
+	}
+	if _, ok := metadata["name"]; !ok && doc.Filename != "" {
+		metadata["name"] = doc.Filename
+	}
+	if len(metadata) > 0 {
+		body[config.OptPinataMetadata] = metadata
+	}
+
+	var res []byte
+	err := retry.Do(ctx, p.RetryPolicy, p.IsRetryable, func(int) error {
+		var err error
+		res, err = p.Client.PostJSON(ctx, config.URLPinataPinJSON, body)
+		return err
+	})
 	if err != nil {
 		return nil, &errors.BifrostError{
 			Err:       err,
@@ -126,18 +272,17 @@ func (p *PinataCloud) UploadFile(fileFace interface{}) (*types.UploadedFile, err
 
 	if obj.Error != "" {
 		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("failed to upload file: %s", obj.Error),
+			Err:       fmt.Errorf("failed to pin json: %s", obj.Error),
 			ErrorCode: errors.ErrBadRequest,
 		}
 	}
 
 	return &types.UploadedFile{
+		Name:           doc.Filename,
 		Size:           obj.PinSize,
 		CID:            obj.IpfsHash,
 		Preview:        fmt.Sprintf(config.URLPinataGateway, obj.IpfsHash),
 		ProviderObject: obj,
-		Name:           filepath.Base(bFile.Path),
-		Path:           bFile.Path,
 		URL:            fmt.Sprintf(config.URLPinataGateway, obj.IpfsHash),
 	}, nil
 }
@@ -157,25 +302,46 @@ func (p *PinataCloud) Disconnect() error {
 // Config returns the Pinata Cloud configuration.
 func (p *PinataCloud) Config() *types.BridgeConfig {
 	return &types.BridgeConfig{
-		Provider:       p.Provider,
-		DefaultTimeout: p.DefaultTimeout,
-		PinataJWT:      p.PinataJWT,
-		EnableDebug:    p.EnableDebug,
-		UseAsync:       p.UseAsync,
-		PublicRead:     p.PublicRead,
+		Provider:           p.Provider,
+		DefaultTimeout:     p.DefaultTimeout,
+		PinataJWT:          p.PinataJWT,
+		PinataAPIKey:       p.PinataAPIKey,
+		PinataSecretAPIKey: p.PinataSecretAPIKey,
+		EnableDebug:        p.EnableDebug,
+		UseAsync:           p.UseAsync,
+		MaxConcurrency:     p.MaxConcurrency,
+		RetryPolicy:        p.RetryPolicy,
+		PublicRead:         p.PublicRead,
+		PinPollInterval:    p.PinPollInterval,
+		VerifyCID:          p.VerifyCID,
 	}
 }
 
-// Preflight attempts to authenticate with Pinata and returns an error if one occurs.
+/*
+Preflight attempts to authenticate with Pinata and returns an error if one occurs.
+The JWT is used when it is set, otherwise the API key and secret are. Credentials rejected by Pinata produce an
+error with the code ErrInvalidCredentials.
+*/
 func (p *PinataCloud) Preflight() error {
+	return p.PreflightContext(context.Background())
+}
+
+// PreflightContext is like Preflight but uses ctx for cancellation and deadlines.
+func (p *PinataCloud) PreflightContext(ctx context.Context) error {
 	if !p.IsConnected() {
 		return &errors.BifrostError{
 			Err:       fmt.Errorf("no active Pinata client"),
 			ErrorCode: errors.ErrClientError,
 		}
 	}
+	if p.PinataJWT == "" && (p.PinataAPIKey == "" || p.PinataSecretAPIKey == "") {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("pinata JWT or API key and secret are required"),
+			ErrorCode: errors.ErrInvalidCredentials,
+		}
+	}
 	// copy the request
-	req := p.Client.Request.Clone(p.Client.Request.Context())
+	req := p.Client.Request.Clone(ctx)
 	req.URL, _ = req.URL.Parse(config.URLPinataAuth)
 	req.Method = config.MethodGet
 	res, err := p.Client.Http.Do(req)
@@ -194,6 +360,21 @@ func (p *PinataCloud) Preflight() error {
 		}
 	}
 	var par types.PinataAuthResponse
+	// the body of a rejected request is not always JSON, so the status code is checked first
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		json.Unmarshal(b, &par)
+		reason := par.Error.Details
+		if reason == "" {
+			reason = par.Error.Reason
+		}
+		if reason == "" {
+			reason = http.StatusText(res.StatusCode)
+		}
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("invalid Pinata credentials: %s", reason),
+			ErrorCode: errors.ErrInvalidCredentials,
+		}
+	}
 	err = json.Unmarshal(b, &par)
 	if err != nil {
 		return &errors.BifrostError{
@@ -215,27 +396,97 @@ func (p *PinataCloud) IsConnected() bool {
 	return p.Client != nil
 }
 
-/*
-UploadFolder uploads a folder to the provider storage and returns an error if one occurs.
+// Ping authenticates with Pinata through PreflightContext and returns an error if one occurs.
+func (p *PinataCloud) Ping(ctx context.Context) error {
+	return p.PreflightContext(ctx)
+}
 
-Note: for some providers, UploadFolder requires that a default bucket be set in bifrost.BridgeConfig.
+/*
+UploadFolder uploads every file in a local folder to Pinata and returns an error if one occurs.
+Each file is stored under its path relative to Folder.Path, behind Folder.Prefix, and Folder.Options are merged into
+each file's options the same way as MultiFile.GlobalOptions. Like UploadMultiFile, one UploadedFile is returned per file with
+any failure recorded in its Error field.
 */
 func (p *PinataCloud) UploadFolder(foldFace interface{}) ([]*types.UploadedFile, error) {
-	return nil, nil
+	return p.UploadFolderContext(context.Background(), foldFace)
 }
 
-// UploadMultiFile
-func (p *PinataCloud) UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error) {
+// UploadFolderContext is like UploadFolder but uses ctx for cancellation and deadlines of every upload.
+func (p *PinataCloud) UploadFolderContext(ctx context.Context, foldFace interface{}) ([]*types.UploadedFile, error) {
+	folder, err := types.AsFolder(foldFace)
+	if err != nil {
+		return nil, err
+	}
+	return p.UploadDir(ctx, folder)
+}
 
-	// assert that the multiFace is of type bifrost.File
-	multiFile, ok := multiFace.(types.MultiFile)
-	if !ok {
+/*
+UploadDir pins every file in a local folder to Pinata as a single IPFS directory and returns an error if one occurs.
+It is the typed equivalent of UploadFolderContext.
+
+The files are sent in one request with their paths relative to the folder, so that they resolve as ipfs/<CID>/<path>.
+Every returned file carries the CID of the directory and its gateway URL under it. The pin is named after the folder
+unless the pinataMetadata option names it, and per-file options are not supported.
+*/
+func (p *PinataCloud) UploadDir(ctx context.Context, folder types.Folder) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := folder.Validate(); err != nil {
 		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("argument must be of type bifrost.MultiFile"),
-			ErrorCode: errors.ErrBadRequest,
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	files, err := walk.Files(folder)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if len(files) == 0 {
+		return []*types.UploadedFile{}, nil
+	}
+
+	if !p.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Pinata client"),
+			ErrorCode: errors.ErrClientError,
 		}
 	}
 
+	dir := filepath.Base(folder.Path)
+	if abs, err := filepath.Abs(folder.Path); err == nil {
+		dir = filepath.Base(abs)
+	}
+	return p.uploadDirectory(ctx, dir, types.MultiFile{
+		Files:         files,
+		GlobalOptions: folder.Options,
+	})
+}
+
+// UploadMultiFile
+func (p *PinataCloud) UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error) {
+	return p.UploadMultiFileContext(context.Background(), multiFace)
+}
+
+// UploadMultiFileContext is like UploadMultiFile but uses ctx for cancellation and deadlines of every upload.
+func (p *PinataCloud) UploadMultiFileContext(ctx context.Context, multiFace interface{}) ([]*types.UploadedFile, error) {
+	multiFile, err := types.AsMultiFile(multiFace)
+	if err != nil {
+		return nil, err
+	}
+	return p.UploadMulti(ctx, multiFile)
+}
+
+/*
+UploadMulti uploads multiple files to Pinata and returns an error if one occurs. It is the typed equivalent of UploadMultiFileContext.
+
+When the pinataOptions of GlobalOptions enable wrapWithDirectory, the files are pinned together as a single IPFS directory
+in one request instead, with every returned file carrying the CID of the directory and its gateway URL under it.
+The upload then succeeds or fails as a whole, and the options of each file are not sent.
+*/
+func (p *PinataCloud) UploadMulti(ctx context.Context, multiFile types.MultiFile) ([]*types.UploadedFile, error) {
 	// validate struct
 	if err := multiFile.Validate(); err != nil {
 		return nil, &errors.BifrostError{
@@ -246,45 +497,418 @@ func (p *PinataCloud) UploadMultiFile(multiFace interface{}) ([]*types.UploadedF
 
 	if !p.IsConnected() {
 		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("no active Google Cloud Storage client"),
+			Err:       fmt.Errorf("no active Pinata client"),
 			ErrorCode: errors.ErrClientError,
 		}
 	}
 
-	uploadedFiles := make([]*types.UploadedFile, 0, len(multiFile.Files))
+	if wrapsDirectory(multiFile.GlobalOptions) {
+		return p.uploadDirectory(ctx, "", multiFile)
+	}
 
-	// @TODO: add concurrency when UseAsync is true
-	for _, file := range multiFile.Files {
-		if multiFile.GlobalOptions != nil {
-			// merge global options with file options
-			for k, v := range multiFile.GlobalOptions {
-				// don't override if file has option already
-				if _, ok := file.Options[k]; !ok {
-					file.Options[k] = v
-				}
-			}
-		}
+	uploadedFiles := make([]*types.UploadedFile, len(multiFile.Files))
+	batch := progress.NewBatch(multiFile)
 
-		uploadedFile, err := p.UploadFile(file)
+	// upload concurrently when UseAsync is true, writing each result at its file's index to keep the order of multiFile.Files
+	pool.Run(len(multiFile.Files), pool.Size(p.UseAsync, p.MaxConcurrency), func(i int) {
+		// merge global options into a copy of the file options so that shared maps are never written to
+		file := batch.Track(i, multiFile.Merge(i))
+
+		uploadedFile, err := p.Upload(ctx, file)
+		batch.Done(i, err)
 		if err != nil {
 			if p.EnableDebug {
 				// log failed file and continue
 				log.Printf("Upload for file at path %s failed with err: %s\n", file.Path, err.Error())
 			}
-			uploadedFiles = append(uploadedFiles, &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path})
-			continue
+			uploadedFiles[i] = &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path}
+			return
 		}
-		uploadedFiles = append(uploadedFiles, uploadedFile)
-	}
+		uploadedFiles[i] = uploadedFile
+	})
 
 	return uploadedFiles, nil
 }
 
 /*
-DeleteFile deletes a file from Pinata and returns an error if one occurs.
-
-Note: DeleteFile requires that a default bucket be set in bifrost.BridgeConfig.
+DeleteFile unpins a file from Pinata and returns an error if one occurs.
+The file is identified by DeleteFile.CID, falling back to DeleteFile.Filename when no CID is set.
+If the CID is not pinned by the account, an error with the code ErrNotFound is returned.
 */
 func (p *PinataCloud) DeleteFile(fileFace interface{}) error {
+	return p.DeleteFileContext(context.Background(), fileFace)
+}
+
+// DeleteFileContext is like DeleteFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (p *PinataCloud) DeleteFileContext(ctx context.Context, fileFace interface{}) error {
+	dFile, err := types.AsDeleteFile(fileFace)
+	if err != nil {
+		return err
+	}
+	return p.Delete(ctx, dFile)
+}
+
+// Delete unpins a file from Pinata and returns an error if one occurs. It is the typed equivalent of DeleteFileContext.
+func (p *PinataCloud) Delete(ctx context.Context, dFile types.DeleteFile) error {
+	// validate struct
+	if err := dFile.Validate(); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !p.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active Pinata client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+
+	cid := dFile.CID
+	if cid == "" {
+		cid = dFile.Filename
+	}
+
+	if _, err := p.Client.Delete(ctx, fmt.Sprintf(config.URLPinataUnpin, url.PathEscape(cid))); err != nil {
+		if isNotPinned(err) {
+			return &errors.BifrostError{
+				Err:       fmt.Errorf("file is not pinned: %s", cid),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
 	return nil
 }
+
+/*
+OpenReader opens a file pinned to IPFS for reading through the Pinata gateway and returns its contents along with its attributes.
+name is a CID, optionally followed by a path within it (e.g. "<cid>/metadata.json").
+If the file does not exist, an error with the code ErrNotFound is returned.
+The caller must close the returned reader.
+*/
+func (p *PinataCloud) OpenReader(name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	return p.OpenReaderContext(context.Background(), name)
+}
+
+// OpenReaderContext is like OpenReader but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx
+// and covers reading the returned reader.
+func (p *PinataCloud) OpenReaderContext(ctx context.Context, name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	if name == "" {
+		return nil, nil, &errors.BifrostError{
+			Err:       fmt.Errorf("name is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !p.IsConnected() {
+		return nil, nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Pinata client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// the context must outlive this call as the body is read by the caller, so it is cancelled when the reader is closed
+	var cancel context.CancelFunc
+	if p.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.DefaultTimeout)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	link := fmt.Sprintf(config.URLPinataGateway, name)
+	// the gateway is public, so the request is built without the API credentials
+	req, err := http.NewRequestWithContext(ctx, config.MethodGet, link, nil)
+	if err != nil {
+		cancel()
+		return nil, nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+	res, err := p.Client.Http.Do(req)
+	if err != nil {
+		cancel()
+		return nil, nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer cancel()
+		defer res.Body.Close()
+		b, _ := io.ReadAll(res.Body)
+		if res.StatusCode == http.StatusNotFound {
+			return nil, nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s", name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, nil, &errors.BifrostError{
+			Err:       &request.ResponseError{StatusCode: res.StatusCode, Body: b},
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	info := &types.ObjectInfo{
+		Name:           name,
+		Size:           res.ContentLength,
+		ContentType:    res.Header.Get(config.ReqContentType),
+		ETag:           strings.Trim(res.Header.Get("Etag"), `"`),
+		CID:            strings.SplitN(name, "/", 2)[0],
+		URL:            link,
+		ProviderObject: res,
+	}
+	if t, err := http.ParseTime(res.Header.Get("Last-Modified")); err == nil {
+		info.Updated = t
+	}
+	return stream.CancelOnClose(res.Body, cancel), info, nil
+}
+
+/*
+DownloadFile downloads a file from Pinata to localPath and returns its attributes.
+localPath is only replaced once the whole file has been downloaded.
+*/
+func (p *PinataCloud) DownloadFile(name, localPath string) (*types.ObjectInfo, error) {
+	return p.DownloadFileContext(context.Background(), name, localPath)
+}
+
+// DownloadFileContext is like DownloadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (p *PinataCloud) DownloadFileContext(ctx context.Context, name, localPath string) (*types.ObjectInfo, error) {
+	if localPath == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("localPath is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	rc, info, err := p.OpenReaderContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if info.Size, err = stream.SaveFile(localPath, rc); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return info, nil
+}
+
+/*
+ListFiles lists the files in the pins of the Pinata account one page at a time and returns an error if one occurs.
+Pass ListResult.NextToken as ListOptions.ContinuationToken to list the following page.
+ListOptions.Prefix is matched against the pin names and the continuation token is a page offset. ListOptions.Delimiter is not supported.
+*/
+func (p *PinataCloud) ListFiles(opts types.ListOptions) (*types.ListResult, error) {
+	return p.ListFilesContext(context.Background(), opts)
+}
+
+// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (p *PinataCloud) ListFilesContext(ctx context.Context, opts types.ListOptions) (*types.ListResult, error) {
+
+	// validate struct
+	if err := opts.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !p.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Pinata client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if p.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	var offset int
+	q := url.Values{}
+	q.Set("status", "pinned")
+	if opts.Prefix != "" {
+		q.Set("metadata[name]", opts.Prefix)
+	}
+	if opts.PageSize > 0 {
+		q.Set("pageLimit", strconv.Itoa(opts.PageSize))
+	}
+	if opts.ContinuationToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(opts.ContinuationToken); err != nil || offset < 0 {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("invalid continuation token: %s", opts.ContinuationToken),
+				ErrorCode: errors.ErrInvalidParameters,
+			}
+		}
+		q.Set("pageOffset", opts.ContinuationToken)
+	}
+
+	res, err := p.Client.Get(ctx, config.URLPinataPinList+"?"+q.Encode())
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	var obj types.PinataPinListResponse
+	if err := json.Unmarshal(res, &obj); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("failed to unmarshal response: %s", err.Error()),
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	result := &types.ListResult{
+		Files: make([]*types.ObjectInfo, 0, len(obj.Rows)),
+	}
+	for _, pin := range obj.Rows {
+		// Pinata matches the name filter anywhere in the name, keep only the names that start with the prefix
+		if !strings.HasPrefix(pin.Metadata.Name, opts.Prefix) {
+			continue
+		}
+		result.Files = append(result.Files, pinInfo(pin))
+	}
+	if next := offset + len(obj.Rows); len(obj.Rows) > 0 && int64(next) < obj.Count {
+		result.NextToken = strconv.Itoa(next)
+	}
+	return result, nil
+}
+
+// pinInfo converts a Pinata pin to a types.ObjectInfo.
+func pinInfo(pin types.PinataPin) *types.ObjectInfo {
+	metadata := make(map[string]string, len(pin.Metadata.KeyValues))
+	for k, v := range pin.Metadata.KeyValues {
+		metadata[k] = fmt.Sprint(v)
+	}
+	return &types.ObjectInfo{
+		Name:           pin.Metadata.Name,
+		Size:           pin.Size,
+		ContentType:    pin.MimeType,
+		Metadata:       metadata,
+		ETag:           pin.IpfsPinHash,
+		CID:            pin.IpfsPinHash,
+		Created:        pin.DatePinned,
+		Updated:        pin.DatePinned,
+		URL:            fmt.Sprintf(config.URLPinataGateway, pin.IpfsPinHash),
+		ProviderObject: pin,
+	}
+}
+
+/*
+StatFile returns the attributes of a file pinned by the Pinata account and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
+The file is looked up by its CID, which is passed as name.
+*/
+func (p *PinataCloud) StatFile(name string) (*types.ObjectInfo, error) {
+	return p.StatFileContext(context.Background(), name)
+}
+
+// StatFileContext is like StatFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (p *PinataCloud) StatFileContext(ctx context.Context, name string) (*types.ObjectInfo, error) {
+	if name == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("name is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !p.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active Pinata client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if p.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	q := url.Values{}
+	q.Set("status", "pinned")
+	q.Set("hashContains", name)
+
+	res, err := p.Client.Get(ctx, config.URLPinataPinList+"?"+q.Encode())
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	var obj types.PinataPinListResponse
+	if err := json.Unmarshal(res, &obj); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("failed to unmarshal response: %s", err.Error()),
+			ErrorCode: errors.ErrBadRequest,
+		}
+	}
+
+	// hashContains is a substring match, so look for the exact CID
+	for _, pin := range obj.Rows {
+		if pin.IpfsPinHash == name {
+			return pinInfo(pin), nil
+		}
+	}
+	return nil, &errors.BifrostError{
+		Err:       fmt.Errorf("file is not pinned: %s", name),
+		ErrorCode: errors.ErrNotFound,
+	}
+}
+
+/*
+Exists returns true if a file is pinned by the Pinata account and returns an error if the lookup fails.
+The file is looked up by its CID, which is passed as name.
+*/
+func (p *PinataCloud) Exists(name string) (bool, error) {
+	return p.ExistsContext(context.Background(), name)
+}
+
+// ExistsContext is like Exists but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (p *PinataCloud) ExistsContext(ctx context.Context, name string) (bool, error) {
+	if _, err := p.StatFileContext(ctx, name); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// isNotPinned returns true if err is a Pinata response error for a CID that is not pinned by the account.
+func isNotPinned(err error) bool {
+	var re *request.ResponseError
+	if !errors.As(err, &re) {
+		return false
+	}
+	if re.StatusCode == http.StatusNotFound {
+		return true
+	}
+	var per types.PinataErrorResponse
+	if err := json.Unmarshal(re.Body, &per); err != nil {
+		return false
+	}
+	return per.Error.Reason == pinataReasonNotPinned
+}
+
+// IsRetryable returns true if err is a transient Pinata failure, such as a rate limited request, a 5xx response or a
+// dropped connection, that is worth retrying.
+func (p *PinataCloud) IsRetryable(err error) bool {
+	var re *request.ResponseError
+	if errors.As(err, &re) {
+		return retry.IsRetryableStatus(re.StatusCode)
+	}
+	return retry.IsTransient(err)
+}
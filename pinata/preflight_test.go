@@ -0,0 +1,71 @@
+package pinata
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/request"
+)
+
+func TestPreflight(t *testing.T) {
+	// the fake accepts the key pair "key"/"secret" and rejects anything else like Pinata does
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/testAuthentication" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("pinata_api_key") != "key" || r.Header.Get("pinata_secret_api_key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"reason":"INVALID_API_KEYS","details":"Invalid API key provided"}}`))
+			return
+		}
+		w.Write([]byte(`{"message":"Congratulations! You are communicating with the Pinata API!"}`))
+	}
+	newKeyPinata := func(t *testing.T, key, secret string) *PinataCloud {
+		p := newFakePinata(t, handler)
+		target, _ := url.Parse(p.Client.Request.URL.String())
+		p.Client = request.NewKeyClient(target.String(), key, secret, 10)
+		p.Client.Http.Transport = rewrite{target: target}
+		p.PinataJWT, p.PinataAPIKey, p.PinataSecretAPIKey = "", key, secret
+		return p
+	}
+
+	t.Run("Tests Preflight method with an API key and secret", func(t *testing.T) {
+		if err := newKeyPinata(t, "key", "secret").Preflight(); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Tests Preflight method with a bad API secret", func(t *testing.T) {
+		err := newKeyPinata(t, "key", "wrong").PreflightContext(context.Background())
+		if code := errorCode(err); code != errors.ErrInvalidCredentials {
+			t.Errorf("Expected %s error, got: %v", errors.ErrInvalidCredentials, err)
+		}
+	})
+
+	t.Run("Tests Preflight method without a secret", func(t *testing.T) {
+		err := newKeyPinata(t, "key", "").Preflight()
+		if code := errorCode(err); code != errors.ErrInvalidCredentials {
+			t.Errorf("Expected %s error, got: %v", errors.ErrInvalidCredentials, err)
+		}
+	})
+
+	t.Run("Tests Preflight method with a bad JWT", func(t *testing.T) {
+		err := newFakePinata(t, handler).Preflight()
+		if code := errorCode(err); code != errors.ErrInvalidCredentials {
+			t.Errorf("Expected %s error, got: %v", errors.ErrInvalidCredentials, err)
+		}
+	})
+}
+
+// errorCode returns the code of a Bifrost error, or an empty string for any other error.
+func errorCode(err error) string {
+	var be *errors.BifrostError
+	if errors.As(err, &be) {
+		return be.Code()
+	}
+	return ""
+}
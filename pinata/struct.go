@@ -1,6 +1,8 @@
 package pinata
 
 import (
+	"time"
+
 	"github.com/opensaucerer/bifrost/shared/request"
 	"github.com/opensaucerer/bifrost/shared/types"
 )
@@ -15,10 +17,34 @@ type PinataCloud struct {
 	PublicRead bool
 	// Pinata authorization JWT
 	PinataJWT string
+	// Pinata API key, used along with PinataSecretAPIKey when PinataJWT is not set
+	PinataAPIKey string
+	// Pinata API secret
+	PinataSecretAPIKey string
 	// UseAsync enables asynchronous operations with go routines.
 	UseAsync bool
+	// MaxConcurrency is the maximum number of concurrent uploads when UseAsync is enabled.
+	MaxConcurrency int
+	// RetryPolicy configures how failed uploads are retried. Uploads are not retried when it is nil.
+	RetryPolicy *types.RetryPolicy
+	// VerifyCID checks that the IpfsHash returned for every uploaded file matches the CID computed from its content.
+	VerifyCID bool
+	// PinPollInterval is the delay between two polls of a pin job. It defaults to config.DefaultPinPollInterval.
+	PinPollInterval time.Duration
 	// Pinata request client
 	Client *request.Client
 	// EnableDebug enables debug logging.
 	EnableDebug bool
 }
+
+// pinataReasonNotPinned is the error reason Pinata returns when unpinning a CID the account has not pinned.
+const pinataReasonNotPinned = "CURRENT_USER_HAS_NOT_PINNED_CID"
+
+// pinataContent is the request field holding the document pinned with pinJSONToIPFS.
+const pinataContent = "pinataContent"
+
+// pinataHashToPin is the request field holding the CID pinned with pinByHash.
+const pinataHashToPin = "hashToPin"
+
+// pinataWrapWithDirectory is the pinataOptions field that wraps pinned files in a directory.
+const pinataWrapWithDirectory = "wrapWithDirectory"
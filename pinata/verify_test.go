@@ -0,0 +1,78 @@
+package pinata
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+func TestVerifyCID(t *testing.T) {
+	// the fake pins every file as hash, read from the body so that streamed handles are consumed
+	newVerifyingPinata := func(t *testing.T, hash string) *PinataCloud {
+		p := newFakePinata(t, func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			w.Write([]byte(`{"IpfsHash":"` + hash + `","PinSize":11}`))
+		})
+		p.VerifyCID = true
+		return p
+	}
+
+	t.Run("Tests UploadFile method with a matching CID", func(t *testing.T) {
+		p := newVerifyingPinata(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD")
+		path := filepath.Join(t.TempDir(), "hello.txt")
+		os.WriteFile(path, []byte("hello world"), 0o644)
+
+		o, err := p.UploadFile(types.File{Path: path})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if o.CID != "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD" {
+			t.Errorf("Unexpected CID %s", o.CID)
+		}
+	})
+
+	t.Run("Tests UploadFile method respects cidVersion for streamed handles", func(t *testing.T) {
+		p := newVerifyingPinata(t, "bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e")
+
+		if _, err := p.UploadFile(types.File{
+			Handle:   io.MultiReader(strings.NewReader("hello world")),
+			Filename: "hello.txt",
+			Options: map[string]interface{}{
+				config.OptPinata: map[string]interface{}{"cidVersion": 1},
+			},
+		}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Tests UploadFile method with a mismatched CID", func(t *testing.T) {
+		p := newVerifyingPinata(t, "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o")
+
+		_, err := p.UploadFile(types.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		})
+		if code := errorCode(err); code != errors.ErrIntegrity {
+			t.Errorf("Expected %s error, got: %v", errors.ErrIntegrity, err)
+		}
+	})
+
+	t.Run("Tests UploadFile method without VerifyCID", func(t *testing.T) {
+		p := newVerifyingPinata(t, "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o")
+		p.VerifyCID = false
+
+		if _, err := p.UploadFile(types.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package bifrost
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// ProviderFactory creates a rainbow bridge to a provider from the bridge configuration.
+type ProviderFactory func(*BridgeConfig) (RainbowBridge, error)
+
+// Capability is a feature that a provider may support.
+type Capability string
+
+// Capability constants.
+const (
+	// CapACL is the capability of applying OptACL to uploaded files.
+	CapACL Capability = "acl"
+	// CapContentType is the capability of storing the OptContentType of uploaded files.
+	CapContentType Capability = "content-type"
+	// CapMetadata is the capability of storing the OptMetadata of uploaded files.
+	CapMetadata Capability = "metadata"
+	// CapMultipart is the capability of uploading large files in parts.
+	CapMultipart Capability = "multipart"
+	// CapDelimiter is the capability of grouping listed files into prefixes with ListOptions.Delimiter.
+	CapDelimiter Capability = "delimiter"
+	// CapContentAddressed is the capability of addressing files by the hash of their contents (e.g. an IPFS CID).
+	CapContentAddressed Capability = "content-addressed"
+)
+
+// ProviderInfo describes a provider registered with RegisterProvider.
+type ProviderInfo struct {
+	// Name is the identifier of the provider, as set in BridgeConfig.Provider.
+	Name types.Provider
+	// Capabilities is the list of features the provider supports.
+	Capabilities []Capability
+}
+
+// Supports returns true if the provider has the capability c.
+func (p ProviderInfo) Supports(c Capability) bool {
+	for _, capability := range p.Capabilities {
+		if capability == c {
+			return true
+		}
+	}
+	return false
+}
+
+// registration is a provider in the registry.
+type registration struct {
+	info    ProviderInfo
+	factory ProviderFactory
+}
+
+var (
+	// registryMu guards registry
+	registryMu sync.RWMutex
+	// registry maps the identifier of every registered provider to its factory
+	registry = map[types.Provider]registration{}
+)
+
+/*
+RegisterProvider makes a provider available to NewRainbowBridge under name, along with the capabilities it supports.
+NewRainbowBridge calls factory with the bridge configuration whenever BridgeConfig.Provider is name.
+
+RegisterProvider is meant to be called from the init function of the package implementing the provider. It returns an error
+with the code ErrInvalidProvider if name is empty, factory is nil or a provider is already registered under name.
+The built-in providers are registered the same way.
+*/
+func RegisterProvider(name types.Provider, factory ProviderFactory, capabilities ...Capability) error {
+	if name == "" {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("provider name is required"),
+			ErrorCode: errors.ErrInvalidProvider,
+		}
+	}
+	if factory == nil {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("provider factory is nil: %s", name),
+			ErrorCode: errors.ErrInvalidProvider,
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("provider already registered: %s", name),
+			ErrorCode: errors.ErrInvalidProvider,
+		}
+	}
+	registry[name] = registration{
+		info: ProviderInfo{
+			Name:         name,
+			Capabilities: append([]Capability(nil), capabilities...),
+		},
+		factory: factory,
+	}
+	return nil
+}
+
+// LookupProvider returns the description of the provider registered under name and false if there is none.
+func LookupProvider(name types.Provider) (ProviderInfo, bool) {
+	r, ok := lookup(name)
+	return r.info, ok
+}
+
+// Providers returns the description of every registered provider, sorted by name.
+func Providers() []ProviderInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	infos := make([]ProviderInfo, 0, len(registry))
+	for _, r := range registry {
+		infos = append(infos, r.info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// lookup returns the registration of the provider registered under name.
+func lookup(name types.Provider) (registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := registry[name]
+	return r, ok
+}
+
+// displayName returns the human-readable name of a provider, which is its identifier for third-party providers.
+func displayName(name types.Provider) types.Provider {
+	if n, ok := providers[name]; ok {
+		return n
+	}
+	return name
+}
+
+func init() {
+	// the built-in providers go through the same registry as third-party ones
+	RegisterProvider(SimpleStorageService, newSimpleStorageService, CapACL, CapContentType, CapMetadata, CapMultipart, CapDelimiter)
+	RegisterProvider(GoogleCloudStorage, newGoogleCloudStorage, CapACL, CapContentType, CapMetadata, CapDelimiter)
+	RegisterProvider(PinataCloud, newPinataCloud, CapContentAddressed)
+	RegisterProvider(WasabiCloudStorage, newWasabiCloudStorage, CapACL, CapContentType, CapMetadata, CapMultipart, CapDelimiter)
+	RegisterProvider(LocalStorage, newLocalStorage, CapACL, CapContentType, CapMetadata, CapDelimiter)
+	RegisterProvider(MemoryStorage, newMemoryStorage, CapACL, CapContentType, CapMetadata, CapDelimiter)
+	RegisterProvider(AzureBlobStorage, newAzureBlobStorage, CapACL, CapContentType, CapMetadata, CapMultipart, CapDelimiter)
+}
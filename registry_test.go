@@ -0,0 +1,99 @@
+package bifrost_test
+
+import (
+	"testing"
+
+	"github.com/opensaucerer/bifrost"
+)
+
+func TestRegisterProvider(t *testing.T) {
+	t.Run("Tests RegisterProvider method with a third-party provider", func(t *testing.T) {
+		var got *bifrost.BridgeConfig
+		err := bifrost.RegisterProvider("internal-store", func(bc *bifrost.BridgeConfig) (bifrost.RainbowBridge, error) {
+			got = bc
+			return bifrost.NewRainbowBridge(&bifrost.BridgeConfig{
+				Provider:      bifrost.MemoryStorage,
+				DefaultBucket: bc.DefaultBucket,
+			})
+		}, bifrost.CapMetadata)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		bridge, err := bifrost.NewRainbowBridge(&bifrost.BridgeConfig{
+			Provider:      "internal-store",
+			DefaultBucket: "bucket",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer bridge.Disconnect()
+		if got == nil || got.DefaultBucket != "bucket" {
+			t.Errorf("Expected the factory to receive the bridge configuration, got %+v", got)
+		}
+
+		info, ok := bifrost.LookupProvider("internal-store")
+		if !ok {
+			t.Fatalf("Expected internal-store to be registered")
+		}
+		if !info.Supports(bifrost.CapMetadata) || info.Supports(bifrost.CapMultipart) {
+			t.Errorf("Unexpected capabilities %v", info.Capabilities)
+		}
+	})
+
+	t.Run("Tests RegisterProvider method with a taken name", func(t *testing.T) {
+		err := bifrost.RegisterProvider(bifrost.SimpleStorageService, func(bc *bifrost.BridgeConfig) (bifrost.RainbowBridge, error) {
+			return nil, nil
+		})
+		if err == nil || err.(bifrost.Error).Code() != bifrost.ErrInvalidProvider {
+			t.Errorf("Expected %s error, got: %v", bifrost.ErrInvalidProvider, err)
+		}
+	})
+
+	t.Run("Tests RegisterProvider method with invalid arguments", func(t *testing.T) {
+		if err := bifrost.RegisterProvider("", func(bc *bifrost.BridgeConfig) (bifrost.RainbowBridge, error) {
+			return nil, nil
+		}); err == nil {
+			t.Errorf("Expected an empty name to be rejected")
+		}
+		if err := bifrost.RegisterProvider("nil-factory", nil); err == nil {
+			t.Errorf("Expected a nil factory to be rejected")
+		}
+		if _, ok := bifrost.LookupProvider("nil-factory"); ok {
+			t.Errorf("Expected nil-factory not to be registered")
+		}
+	})
+
+	t.Run("Tests Providers method lists the built-in providers", func(t *testing.T) {
+		registered := map[bifrost.Provider]bifrost.ProviderInfo{}
+		for _, info := range bifrost.Providers() {
+			registered[info.Name] = info
+		}
+		for _, name := range []bifrost.Provider{
+			bifrost.SimpleStorageService,
+			bifrost.GoogleCloudStorage,
+			bifrost.PinataCloud,
+			bifrost.WasabiCloudStorage,
+			bifrost.LocalStorage,
+			bifrost.MemoryStorage,
+			bifrost.AzureBlobStorage,
+		} {
+			if _, ok := registered[name]; !ok {
+				t.Errorf("Expected %s to be registered", name)
+			}
+		}
+		if !registered[bifrost.SimpleStorageService].Supports(bifrost.CapMultipart) {
+			t.Errorf("Expected %s to support %s", bifrost.SimpleStorageService, bifrost.CapMultipart)
+		}
+		if !registered[bifrost.PinataCloud].Supports(bifrost.CapContentAddressed) {
+			t.Errorf("Expected %s to support %s", bifrost.PinataCloud, bifrost.CapContentAddressed)
+		}
+	})
+
+	t.Run("Tests NewRainbowBridge method with an unregistered provider", func(t *testing.T) {
+		_, err := bifrost.NewRainbowBridge(&bifrost.BridgeConfig{Provider: "does-not-exist"})
+		if err == nil || err.(bifrost.Error).Code() != bifrost.ErrInvalidProvider {
+			t.Errorf("Expected %s error, got: %v", bifrost.ErrInvalidProvider, err)
+		}
+	})
+}
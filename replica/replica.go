@@ -0,0 +1,631 @@
+/*
+Package replica provides a rainbow bridge that replicates files to several bridges at once.
+
+Uploads and deletes are sent to every bridge concurrently and succeed once the configured quorum of bridges succeeded.
+The outcome on each bridge is reported in the ProviderObject of the returned UploadedFile, as a []Result in the order of the
+bridges, which Result.Wait completes for the bridges still replicating in the background. Reads go to the first bridge, falling back to the next ones when the file cannot be read from it.
+
+	bridge, err := replica.NewBridge([]bifrost.RainbowBridge{s3Bridge, pinataBridge}, replica.Options{
+		Quorum:     replica.QuorumAny,
+		Background: true,
+	})
+*/
+package replica
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opensaucerer/bifrost"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/pool"
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/stream"
+	"github.com/opensaucerer/bifrost/shared/types"
+	"github.com/opensaucerer/bifrost/shared/walk"
+)
+
+// NewBridge returns a rainbow bridge replicating to bridges, the first of which is the primary bridge that reads go to.
+func NewBridge(bridges []bifrost.RainbowBridge, opts Options) (*Bridge, error) {
+	if len(bridges) == 0 {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("at least one bridge is required"),
+			ErrorCode: errors.ErrInvalidConfig,
+		}
+	}
+	switch opts.Quorum {
+	case "":
+		opts.Quorum = QuorumAll
+	case QuorumAll, QuorumMajority, QuorumAny:
+	default:
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("invalid quorum: %s", opts.Quorum),
+			ErrorCode: errors.ErrInvalidConfig,
+		}
+	}
+
+	b := &Bridge{
+		options:   opts,
+		bridges:   append([]bifrost.RainbowBridge(nil), bridges...),
+		providers: make([]types.Provider, len(bridges)),
+	}
+	for i, bridge := range bridges {
+		if bridge == nil {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("bridge %d is nil", i),
+				ErrorCode: errors.ErrInvalidConfig,
+			}
+		}
+		b.providers[i] = bridge.Config().Provider
+	}
+	return b, nil
+}
+
+// Bridges returns the bridges files are replicated to, in order.
+func (b *Bridge) Bridges() []bifrost.RainbowBridge {
+	return append([]bifrost.RainbowBridge(nil), b.bridges...)
+}
+
+// needed returns the number of bridges a write has to succeed on.
+func (b *Bridge) needed() int {
+	switch b.options.Quorum {
+	case QuorumAny:
+		return 1
+	case QuorumMajority:
+		return len(b.bridges)/2 + 1
+	default:
+		return len(b.bridges)
+	}
+}
+
+/*
+UploadFile uploads a file to every bridge and returns an error if the quorum is not met.
+The ProviderObject of the returned UploadedFile is the []Result of every bridge.
+*/
+func (b *Bridge) UploadFile(fileFace interface{}) (*types.UploadedFile, error) {
+	return b.UploadFileContext(context.Background(), fileFace)
+}
+
+// UploadFileContext is like UploadFile but uses ctx for cancellation and deadlines.
+func (b *Bridge) UploadFileContext(ctx context.Context, fileFace interface{}) (*types.UploadedFile, error) {
+	bFile, err := types.AsFile(fileFace)
+	if err != nil {
+		return nil, err
+	}
+	return b.Upload(ctx, bFile)
+}
+
+/*
+Upload uploads a file to every bridge concurrently and returns an error if the quorum is not met, in which case the error
+wraps a *QuorumError holding the result of every bridge. It is the typed equivalent of UploadFileContext.
+
+The returned UploadedFile is the one of the first bridge, in order, that stored the file, with ProviderObject set to the
+[]Result of every bridge. With Options.Background, Upload returns as soon as the quorum is met and the bridges still
+uploading have ErrPending as their error in ProviderObject, which is never written to afterwards. Result.Wait returns
+their final Result.
+*/
+func (b *Bridge) Upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	// validate struct
+	if err := bFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	src, err := newSource(bFile)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	// background uploads must outlive ctx, which only cancels them until the quorum is met
+	uctx, cancel := ctx, context.CancelFunc(func() {})
+	returned := make(chan struct{})
+	if b.options.Background {
+		uctx, cancel = context.WithCancel(detach(ctx))
+		go func() {
+			select {
+			case <-ctx.Done():
+				// ctx may be cancelled right after Upload returned, which must not stop the background uploads
+				select {
+				case <-returned:
+				default:
+					cancel()
+				}
+			case <-returned:
+			}
+		}()
+	}
+
+	// the uploads send their result rather than write it to results, which is handed to the caller while some of them
+	// may still be running in the background
+	results := make([]Result, len(b.bridges))
+	finished := make(chan outcome, len(b.bridges))
+	for i, bridge := range b.bridges {
+		go func(i int, bridge bifrost.RainbowBridge) {
+			uploadedFile, err := bridge.Upload(uctx, src.file(i))
+			finished <- outcome{i: i, result: Result{Provider: b.providers[i], UploadedFile: uploadedFile, Err: err}}
+		}(i, bridge)
+	}
+
+	needed := b.needed()
+	done, succeeded := make([]bool, len(b.bridges)), 0
+	for count := 0; count < len(b.bridges); count++ {
+		o := <-finished
+		i := o.i
+		results[i] = o.result
+		done[i] = true
+		if results[i].Err == nil {
+			succeeded++
+		} else if b.options.EnableDebug {
+			log.Printf("Upload of %s to %s failed with err: %s\n", src.name, b.providers[i], results[i].Err.Error())
+		}
+		if b.options.Background && succeeded >= needed {
+			break
+		}
+	}
+	close(returned)
+
+	var uploaded *types.UploadedFile
+	for i := range results {
+		if done[i] && results[i].Err == nil {
+			file := *results[i].UploadedFile
+			file.Provider = b.providers[i]
+			uploaded = &file
+			break
+		}
+	}
+
+	pending := make(map[int]*final)
+	for i := range results {
+		if !done[i] {
+			pending[i] = &final{done: make(chan struct{})}
+			results[i] = Result{Provider: b.providers[i], Err: ErrPending, final: pending[i]}
+		}
+	}
+	if len(pending) == 0 {
+		src.close()
+		cancel()
+	} else {
+		uploaded.Done = make(chan bool, 1)
+		uploaded.Quit = make(chan bool, 1)
+		go replicate(finished, pending, uploaded.Done, uploaded.Quit, cancel, src.close)
+	}
+
+	if succeeded < needed {
+		return nil, &errors.BifrostError{
+			Err:       &QuorumError{Quorum: b.options.Quorum, Needed: needed, Succeeded: succeeded, Results: results},
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	uploaded.ProviderObject = results
+	uploaded.Error = nil
+	return uploaded, nil
+}
+
+// replicate waits for the pending background uploads, cancelling them when quit receives, then cleans up and signals done.
+// The result of each upload is published to its final as soon as it is done.
+func replicate(finished <-chan outcome, pending map[int]*final, done chan<- bool, quit <-chan bool, cancel context.CancelFunc, cleanup func()) {
+	for left := len(pending); left > 0; {
+		select {
+		case o := <-finished:
+			pending[o.i].result = o.result
+			close(pending[o.i].done)
+			left--
+		case <-quit:
+			cancel()
+			quit = nil
+		}
+	}
+	cleanup()
+	cancel()
+	done <- true
+	close(done)
+}
+
+// Wait returns the final Result of the bridge, blocking until its upload is done if it was still running in the background
+// when Upload returned.
+func (r Result) Wait() Result {
+	if r.final == nil {
+		return r
+	}
+	<-r.final.done
+	return r.final.result
+}
+
+// UploadMultiFile uploads multiple files to every bridge.
+func (b *Bridge) UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error) {
+	return b.UploadMultiFileContext(context.Background(), multiFace)
+}
+
+// UploadMultiFileContext is like UploadMultiFile but uses ctx for cancellation and deadlines of every upload.
+func (b *Bridge) UploadMultiFileContext(ctx context.Context, multiFace interface{}) ([]*types.UploadedFile, error) {
+	multiFile, err := types.AsMultiFile(multiFace)
+	if err != nil {
+		return nil, err
+	}
+	return b.UploadMulti(ctx, multiFile)
+}
+
+// UploadMulti uploads multiple files to every bridge and returns an error if one occurs. It is the typed equivalent of UploadMultiFileContext.
+// A file that does not meet the quorum has the error in its Error field.
+func (b *Bridge) UploadMulti(ctx context.Context, multiFile types.MultiFile) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := multiFile.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	uploadedFiles := make([]*types.UploadedFile, len(multiFile.Files))
+	batch := progress.NewBatch(multiFile)
+
+	// upload concurrently when UseAsync is true, writing each result at its file's index to keep the order of multiFile.Files
+	pool.Run(len(multiFile.Files), pool.Size(b.options.UseAsync, b.options.MaxConcurrency), func(i int) {
+		// merge global options into a copy of the file options so that shared maps are never written to
+		file := batch.Track(i, multiFile.Merge(i))
+
+		uploadedFile, err := b.Upload(ctx, file)
+		batch.Done(i, err)
+		if err != nil {
+			uploadedFiles[i] = &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path}
+			return
+		}
+		uploadedFiles[i] = uploadedFile
+	})
+
+	return uploadedFiles, nil
+}
+
+// Config returns the configuration of the primary bridge with Provider set to replica.Provider.
+func (b *Bridge) Config() *types.BridgeConfig {
+	config := *b.bridges[0].Config()
+	config.Provider = Provider
+	config.UseAsync = b.options.UseAsync
+	config.MaxConcurrency = b.options.MaxConcurrency
+	return &config
+}
+
+// Disconnect disconnects every bridge and returns the first error that occurs.
+func (b *Bridge) Disconnect() error {
+	var first error
+	for _, bridge := range b.bridges {
+		if err := bridge.Disconnect(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// IsConnected returns true if enough bridges are connected to meet the quorum.
+func (b *Bridge) IsConnected() bool {
+	connected := 0
+	for _, bridge := range b.bridges {
+		if bridge.IsConnected() {
+			connected++
+		}
+	}
+	return connected >= b.needed()
+}
+
+// UploadFolder uploads every file in a local folder to every bridge. Like UploadMultiFile, one UploadedFile is returned per file.
+func (b *Bridge) UploadFolder(foldFace interface{}) ([]*types.UploadedFile, error) {
+	return b.UploadFolderContext(context.Background(), foldFace)
+}
+
+// UploadFolderContext is like UploadFolder but uses ctx for cancellation and deadlines of every upload.
+func (b *Bridge) UploadFolderContext(ctx context.Context, foldFace interface{}) ([]*types.UploadedFile, error) {
+	folder, err := types.AsFolder(foldFace)
+	if err != nil {
+		return nil, err
+	}
+	return b.UploadDir(ctx, folder)
+}
+
+// UploadDir uploads every file in a local folder to every bridge. It is the typed equivalent of UploadFolderContext.
+func (b *Bridge) UploadDir(ctx context.Context, folder types.Folder) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := folder.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	files, err := walk.Files(folder)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if len(files) == 0 {
+		return []*types.UploadedFile{}, nil
+	}
+
+	return b.UploadMulti(ctx, types.MultiFile{
+		Files:         files,
+		GlobalOptions: folder.Options,
+	})
+}
+
+/*
+DeleteFile deletes a file from every bridge and returns an error if the quorum is not met.
+A bridge that does not have the file counts towards the quorum. If no bridge has the file, an error with the code
+ErrNotFound is returned.
+*/
+func (b *Bridge) DeleteFile(fileFace interface{}) error {
+	return b.DeleteFileContext(context.Background(), fileFace)
+}
+
+// DeleteFileContext is like DeleteFile but uses ctx for cancellation and deadlines.
+func (b *Bridge) DeleteFileContext(ctx context.Context, fileFace interface{}) error {
+	dFile, err := types.AsDeleteFile(fileFace)
+	if err != nil {
+		return err
+	}
+	return b.Delete(ctx, dFile)
+}
+
+// Delete deletes a file from every bridge concurrently and returns an error if the quorum is not met, in which case the
+// error wraps a *QuorumError. It is the typed equivalent of DeleteFileContext.
+func (b *Bridge) Delete(ctx context.Context, dFile types.DeleteFile) error {
+	// validate struct
+	if err := dFile.Validate(); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	results := make([]Result, len(b.bridges))
+	var wg sync.WaitGroup
+	for i, bridge := range b.bridges {
+		results[i].Provider = b.providers[i]
+		wg.Add(1)
+		go func(i int, bridge bifrost.RainbowBridge) {
+			defer wg.Done()
+			results[i].Err = bridge.Delete(ctx, dFile)
+		}(i, bridge)
+	}
+	wg.Wait()
+
+	succeeded, missing := 0, 0
+	for _, result := range results {
+		switch {
+		case result.Err == nil:
+			succeeded++
+		case errors.IsNotFound(result.Err):
+			// the file is gone from this bridge all the same
+			succeeded++
+			missing++
+		}
+	}
+	if missing == len(b.bridges) {
+		return results[0].Err
+	}
+	if needed := b.needed(); succeeded < needed {
+		return &errors.BifrostError{
+			Err:       &QuorumError{Quorum: b.options.Quorum, Needed: needed, Succeeded: succeeded, Results: results},
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return nil
+}
+
+/*
+OpenReader opens a file for reading from the first bridge that has it and returns its contents along with its attributes.
+If no bridge has the file, an error with the code ErrNotFound is returned. The caller must close the returned reader.
+*/
+func (b *Bridge) OpenReader(name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	return b.OpenReaderContext(context.Background(), name)
+}
+
+// OpenReaderContext is like OpenReader but uses ctx for cancellation and deadlines.
+func (b *Bridge) OpenReaderContext(ctx context.Context, name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	var rc io.ReadCloser
+	var info *types.ObjectInfo
+	err := b.read(func(bridge bifrost.RainbowBridge) error {
+		var err error
+		rc, info, err = bridge.OpenReaderContext(ctx, name)
+		return err
+	})
+	return rc, info, err
+}
+
+/*
+DownloadFile downloads a file from the first bridge that has it to localPath and returns its attributes.
+If no bridge has the file, an error with the code ErrNotFound is returned.
+*/
+func (b *Bridge) DownloadFile(name, localPath string) (*types.ObjectInfo, error) {
+	return b.DownloadFileContext(context.Background(), name, localPath)
+}
+
+// DownloadFileContext is like DownloadFile but uses ctx for cancellation and deadlines.
+func (b *Bridge) DownloadFileContext(ctx context.Context, name, localPath string) (*types.ObjectInfo, error) {
+	var info *types.ObjectInfo
+	err := b.read(func(bridge bifrost.RainbowBridge) error {
+		var err error
+		info, err = bridge.DownloadFileContext(ctx, name, localPath)
+		return err
+	})
+	return info, err
+}
+
+// ListFiles lists the files of the first bridge that can list them, one page at a time.
+func (b *Bridge) ListFiles(opts types.ListOptions) (*types.ListResult, error) {
+	return b.ListFilesContext(context.Background(), opts)
+}
+
+// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines.
+func (b *Bridge) ListFilesContext(ctx context.Context, opts types.ListOptions) (*types.ListResult, error) {
+	var result *types.ListResult
+	err := b.read(func(bridge bifrost.RainbowBridge) error {
+		var err error
+		result, err = bridge.ListFilesContext(ctx, opts)
+		return err
+	})
+	return result, err
+}
+
+/*
+StatFile returns the attributes of a file from the first bridge that has it.
+If no bridge has the file, an error with the code ErrNotFound is returned.
+*/
+func (b *Bridge) StatFile(name string) (*types.ObjectInfo, error) {
+	return b.StatFileContext(context.Background(), name)
+}
+
+// StatFileContext is like StatFile but uses ctx for cancellation and deadlines.
+func (b *Bridge) StatFileContext(ctx context.Context, name string) (*types.ObjectInfo, error) {
+	var info *types.ObjectInfo
+	err := b.read(func(bridge bifrost.RainbowBridge) error {
+		var err error
+		info, err = bridge.StatFileContext(ctx, name)
+		return err
+	})
+	return info, err
+}
+
+// Exists returns true if a file exists on any bridge and returns an error if it is on none and a lookup failed.
+func (b *Bridge) Exists(name string) (bool, error) {
+	return b.ExistsContext(context.Background(), name)
+}
+
+// ExistsContext is like Exists but uses ctx for cancellation and deadlines.
+func (b *Bridge) ExistsContext(ctx context.Context, name string) (bool, error) {
+	if _, err := b.StatFileContext(ctx, name); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+/*
+read calls fn with each bridge in order until it succeeds.
+When every bridge fails, the first error that is not ErrNotFound is returned, or the ErrNotFound of the first bridge.
+*/
+func (b *Bridge) read(fn func(bridge bifrost.RainbowBridge) error) error {
+	var notFound, failed error
+	for i, bridge := range b.bridges {
+		err := fn(bridge)
+		if err == nil {
+			return nil
+		}
+		if b.options.EnableDebug {
+			log.Printf("Read from %s failed with err: %s\n", b.providers[i], err.Error())
+		}
+		if errors.IsNotFound(err) {
+			if notFound == nil {
+				notFound = err
+			}
+		} else if failed == nil {
+			failed = err
+		}
+	}
+	if failed != nil {
+		return failed
+	}
+	return notFound
+}
+
+// QuorumError is the error wrapped by the error returned when an upload or delete does not meet the quorum.
+type QuorumError struct {
+	// Quorum is the quorum of the bridge.
+	Quorum Quorum
+	// Needed is the number of bridges that had to succeed.
+	Needed int
+	// Succeeded is the number of bridges that succeeded.
+	Succeeded int
+	// Results is the outcome on every bridge, in order.
+	Results []Result
+}
+
+// Error returns the error message, listing the error of every bridge that failed.
+func (e *QuorumError) Error() string {
+	failures := make([]string, 0, len(e.Results))
+	for _, result := range e.Results {
+		if result.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", result.Provider, result.Err.Error()))
+		}
+	}
+	return fmt.Sprintf("quorum %s not met, %d of %d bridges needed succeeded: %s", e.Quorum, e.Succeeded, e.Needed, strings.Join(failures, "; "))
+}
+
+// source hands every bridge its own copy of the file to upload.
+type source struct {
+	base    types.File
+	name    string
+	section *io.SectionReader
+	cleanup func()
+}
+
+// newSource prepares bFile to be uploaded to several bridges at once. A Handle is read into a section which every bridge
+// reads independently, while files with a Path are opened by every bridge.
+func newSource(bFile types.File) (*source, error) {
+	src := &source{base: bFile, name: bFile.Filename, cleanup: func() {}}
+	if src.name == "" {
+		src.name = bFile.Path
+	}
+	if bFile.Handle != nil {
+		section, cleanup, err := stream.Section(bFile.Handle)
+		if err != nil {
+			return nil, err
+		}
+		src.section, src.cleanup = section, cleanup
+	}
+	return src, nil
+}
+
+// file returns the file to upload to the i-th bridge. Only the upload to the first bridge reports progress.
+func (s *source) file(i int) types.File {
+	file := s.base.Clone()
+	if s.section != nil {
+		file.Handle = io.NewSectionReader(s.section, 0, s.section.Size())
+	}
+	if i > 0 {
+		file.Progress = nil
+	}
+	return file
+}
+
+// close releases the section once every bridge is done with it.
+func (s *source) close() {
+	s.cleanup()
+}
+
+// detached is a context that keeps the values of its parent but is never cancelled.
+type detached struct {
+	context.Context
+}
+
+// detach returns a context with the values of ctx which is not cancelled with ctx.
+func detach(ctx context.Context) context.Context {
+	return detached{ctx}
+}
+
+// Deadline reports that a detached context has no deadline.
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done returns nil as a detached context is never cancelled.
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+// Err returns nil as a detached context is never cancelled.
+func (detached) Err() error {
+	return nil
+}
@@ -0,0 +1,266 @@
+package replica_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/opensaucerer/bifrost"
+	"github.com/opensaucerer/bifrost/bifrosttest"
+	"github.com/opensaucerer/bifrost/replica"
+)
+
+// newReplica returns a replicating bridge over n in-memory bridges.
+func newReplica(t *testing.T, n int, opts replica.Options) (*replica.Bridge, []*bifrosttest.Bridge) {
+	backends := make([]*bifrosttest.Bridge, n)
+	bridges := make([]bifrost.RainbowBridge, n)
+	for i := range backends {
+		backends[i] = bifrosttest.NewBridge(t, &bifrost.BridgeConfig{DefaultBucket: "bucket"})
+		bridges[i] = backends[i]
+	}
+	bridge, err := replica.NewBridge(bridges, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return bridge, backends
+}
+
+func TestReplica(t *testing.T) {
+	t.Run("Tests Upload method replicates to every bridge", func(t *testing.T) {
+		bridge, backends := newReplica(t, 3, replica.Options{})
+
+		// a reader of unknown length is read by every bridge
+		o, err := bridge.Upload(context.Background(), bifrost.File{
+			Handle:   io.MultiReader(strings.NewReader("hello world")),
+			Filename: "hello.txt",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, backend := range backends {
+			backend.AssertStored(t, "hello.txt", []byte("hello world"))
+		}
+
+		results, ok := o.ProviderObject.([]replica.Result)
+		if !ok || len(results) != 3 {
+			t.Fatalf("Expected 3 results, got %#v", o.ProviderObject)
+		}
+		for _, result := range results {
+			if result.Err != nil || result.UploadedFile == nil || result.Provider == "" {
+				t.Errorf("Unexpected result %+v", result)
+			}
+		}
+	})
+
+	t.Run("Tests Upload method with an unmet quorum", func(t *testing.T) {
+		bridge, backends := newReplica(t, 3, replica.Options{Quorum: replica.QuorumAll})
+		backends[1].FailUpload(1, syscall.ECONNRESET)
+
+		_, err := bridge.Upload(context.Background(), bifrost.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		})
+		var qe *replica.QuorumError
+		if !errors.As(err, &qe) {
+			t.Fatalf("Expected a quorum error, got: %v", err)
+		}
+		if qe.Needed != 3 || qe.Succeeded != 2 || qe.Results[1].Err == nil {
+			t.Errorf("Unexpected quorum error %+v", qe)
+		}
+	})
+
+	t.Run("Tests Upload method with a majority quorum", func(t *testing.T) {
+		bridge, backends := newReplica(t, 3, replica.Options{Quorum: replica.QuorumMajority})
+		backends[0].FailUpload(1, syscall.ECONNRESET)
+
+		o, err := bridge.Upload(context.Background(), bifrost.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		results := o.ProviderObject.([]replica.Result)
+		if results[0].Err == nil || results[1].Err != nil || results[2].Err != nil {
+			t.Errorf("Unexpected results %+v", results)
+		}
+		backends[0].AssertNotStored(t, "hello.txt")
+		backends[2].AssertStored(t, "hello.txt", []byte("hello world"))
+	})
+
+	t.Run("Tests Upload method with background replication", func(t *testing.T) {
+		bridge, backends := newReplica(t, 2, replica.Options{Quorum: replica.QuorumAny, Background: true})
+		backends[1].SetLatency(100 * time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		o, err := bridge.Upload(ctx, bifrost.File{
+			Handle:   io.MultiReader(strings.NewReader("hello world")),
+			Filename: "hello.txt",
+		})
+		// cancelling the call must not stop the replication
+		cancel()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		backends[0].AssertStored(t, "hello.txt", []byte("hello world"))
+		if o.Done == nil {
+			t.Fatalf("Expected a Done channel for the background replication")
+		}
+
+		select {
+		case <-o.Done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected the background replication to finish")
+		}
+		backends[1].AssertStored(t, "hello.txt", []byte("hello world"))
+		if result := o.ProviderObject.([]replica.Result)[1].Wait(); result.Err != nil || result.UploadedFile == nil {
+			t.Errorf("Unexpected background result %+v", result)
+		}
+	})
+
+	t.Run("Tests Upload method results while replicating in the background", func(t *testing.T) {
+		// run with -race: ProviderObject is read while the slow bridge is still uploading
+		bridge, backends := newReplica(t, 2, replica.Options{Quorum: replica.QuorumAny, Background: true})
+		backends[1].SetLatency(100 * time.Millisecond)
+
+		o, err := bridge.Upload(context.Background(), bifrost.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		results := o.ProviderObject.([]replica.Result)
+		if results[0].Err != nil || results[1].Err != replica.ErrPending || results[1].UploadedFile != nil {
+			t.Fatalf("Expected the second bridge to be pending, got %+v", results)
+		}
+
+		final := make(chan replica.Result)
+		go func() { final <- results[1].Wait() }()
+		for polling := true; polling; {
+			select {
+			case result := <-final:
+				if result.Err != nil || result.UploadedFile == nil || result.Provider != results[1].Provider {
+					t.Errorf("Unexpected background result %+v", result)
+				}
+				polling = false
+			default:
+				if r := o.ProviderObject.([]replica.Result)[1]; r.Err != replica.ErrPending {
+					t.Fatalf("Expected ProviderObject not to change, got %+v", r)
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}
+		<-o.Done
+		if results[1].Err != replica.ErrPending {
+			t.Errorf("Expected ProviderObject to keep the results taken when Upload returned")
+		}
+	})
+
+	t.Run("Tests Upload method with cancelled background replication", func(t *testing.T) {
+		bridge, backends := newReplica(t, 2, replica.Options{Quorum: replica.QuorumAny, Background: true})
+		backends[1].SetLatency(time.Minute)
+
+		o, err := bridge.Upload(context.Background(), bifrost.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		o.Quit <- true
+
+		select {
+		case <-o.Done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected the background replication to stop")
+		}
+		backends[1].AssertNotStored(t, "hello.txt")
+		if result := o.ProviderObject.([]replica.Result)[1].Wait(); result.Err == nil || result.Err == replica.ErrPending {
+			t.Errorf("Expected the cancelled upload to fail, got %v", result.Err)
+		}
+	})
+
+	t.Run("Tests UploadMultiFile method", func(t *testing.T) {
+		bridge, backends := newReplica(t, 2, replica.Options{UseAsync: true})
+
+		o, err := bridge.UploadMultiFile(bifrost.MultiFile{
+			Files: []bifrost.File{
+				{Handle: strings.NewReader("a"), Filename: "a.txt"},
+				{Handle: strings.NewReader("b"), Filename: "b.txt"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, file := range o {
+			if file.Error != nil {
+				t.Errorf("Unexpected error: %v", file.Error)
+			}
+		}
+		for _, backend := range backends {
+			backend.AssertStored(t, "a.txt", []byte("a"))
+			backend.AssertStored(t, "b.txt", []byte("b"))
+		}
+	})
+
+	t.Run("Tests DeleteFile method", func(t *testing.T) {
+		bridge, backends := newReplica(t, 2, replica.Options{})
+		if _, err := backends[0].Upload(context.Background(), bifrost.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// the file missing from the second bridge does not fail the delete
+		if err := bridge.DeleteFile(bifrost.DeleteFile{Filename: "hello.txt"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		backends[0].AssertNotStored(t, "hello.txt")
+
+		if err := bridge.DeleteFile(bifrost.DeleteFile{Filename: "hello.txt"}); !bifrost.IsNotFound(err) {
+			t.Errorf("Expected %s error, got: %v", bifrost.ErrNotFound, err)
+		}
+	})
+
+	t.Run("Tests StatFile and OpenReader methods fall back to the next bridge", func(t *testing.T) {
+		bridge, backends := newReplica(t, 2, replica.Options{})
+		if _, err := backends[1].Upload(context.Background(), bifrost.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		info, err := bridge.StatFile("hello.txt")
+		if err != nil || info.Size != 11 {
+			t.Fatalf("Unexpected stat %+v: %v", info, err)
+		}
+		rc, _, err := bridge.OpenReader("hello.txt")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer rc.Close()
+		if b, _ := io.ReadAll(rc); string(b) != "hello world" {
+			t.Errorf("Expected hello world, got %q", b)
+		}
+
+		if exists, err := bridge.Exists("missing.txt"); err != nil || exists {
+			t.Errorf("Expected missing.txt not to exist, got %v: %v", exists, err)
+		}
+	})
+
+	t.Run("Tests NewBridge method with invalid options", func(t *testing.T) {
+		if _, err := replica.NewBridge(nil, replica.Options{}); err == nil {
+			t.Errorf("Expected a bridge without backends to be rejected")
+		}
+		if _, err := replica.NewBridge([]bifrost.RainbowBridge{bifrosttest.NewBridge(t, nil)}, replica.Options{Quorum: "most"}); err == nil {
+			t.Errorf("Expected an invalid quorum to be rejected")
+		}
+	})
+}
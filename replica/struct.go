@@ -0,0 +1,76 @@
+package replica
+
+import (
+	"errors"
+
+	"github.com/opensaucerer/bifrost"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// Provider is the provider reported by the Config of a replicating bridge.
+const Provider types.Provider = "replica"
+
+// Quorum is the number of bridges a write has to succeed on for a replicating bridge to report success.
+type Quorum string
+
+// Quorum constants.
+const (
+	// QuorumAll requires a write to succeed on every bridge.
+	QuorumAll Quorum = "all"
+	// QuorumMajority requires a write to succeed on more than half of the bridges.
+	QuorumMajority Quorum = "majority"
+	// QuorumAny requires a write to succeed on at least one bridge.
+	QuorumAny Quorum = "any"
+)
+
+// Options configures a replicating bridge.
+type Options struct {
+	// Quorum is the number of bridges uploads and deletes have to succeed on. It defaults to QuorumAll.
+	Quorum Quorum
+	// Background returns uploads as soon as the quorum is met and finishes replicating to the other bridges in the
+	// background. UploadedFile.Done receives once every bridge is done, and sending on UploadedFile.Quit cancels the
+	// uploads still in progress.
+	Background bool
+	// UseAsync uploads the files of UploadMultiFile concurrently.
+	UseAsync bool
+	// MaxConcurrency is the maximum number of concurrent uploads when UseAsync is enabled.
+	MaxConcurrency int
+	// EnableDebug logs the failures of every bridge.
+	EnableDebug bool
+}
+
+// ErrPending is the error of the bridges that were still uploading in the background when Upload returned.
+var ErrPending = errors.New("upload still in progress in the background")
+
+// Result is the outcome of an operation on one of the bridges of a replicating bridge.
+type Result struct {
+	// Provider is the provider of the bridge.
+	Provider types.Provider
+	// UploadedFile is the file uploaded to the bridge. It is nil for deletes and failed or pending uploads.
+	UploadedFile *types.UploadedFile
+	// Err is the error returned by the bridge, or ErrPending while the upload runs in the background.
+	Err error
+	// final is the outcome of an upload that was still running in the background when Upload returned.
+	final *final
+}
+
+// final is set once an upload running in the background is done.
+type final struct {
+	// done is closed once result is set.
+	done   chan struct{}
+	result Result
+}
+
+// outcome is the Result of the upload to the bridge at index i.
+type outcome struct {
+	i      int
+	result Result
+}
+
+// Bridge is a rainbow bridge that replicates uploads and deletes to several bridges and reads from the first bridge that
+// has the file.
+type Bridge struct {
+	options   Options
+	bridges   []bifrost.RainbowBridge
+	providers []types.Provider
+}
@@ -0,0 +1,74 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/stream"
+)
+
+/*
+putObject uploads params.Body to S3.
+
+Seekable bodies smaller than the multipart threshold are sent with a single PutObject request. Larger bodies and streams of
+unknown length are sent as a multipart upload, which only ever holds PartSize * PartConcurrency bytes in memory.
+A failed multipart upload is aborted so that its parts are not left behind.
+
+Seekable bodies are retried as a whole according to RetryPolicy when it is set, so the requests are then sent without the
+retries of the SDK, which would otherwise multiply the attempts. Streams, which RetryPolicy cannot send again, keep them.
+*/
+func (s *SimpleStorageService) putObject(ctx context.Context, params *s3.PutObjectInput) error {
+	threshold := s.MultipartThreshold
+	if threshold <= 0 {
+		threshold = config.DefaultMultipartThreshold
+	}
+	var optFns []func(*s3.Options)
+	if _, ok := params.Body.(io.ReadSeeker); ok {
+		if s.RetryPolicy != nil {
+			optFns = append(optFns, func(o *s3.Options) {
+				o.Retryer = aws.NopRetryer{}
+			})
+		}
+		if size := stream.Size(params.Body); size >= 0 && size < threshold {
+			_, err := s.Client.PutObject(ctx, params, optFns...)
+			return err
+		}
+	}
+
+	uploader := manager.NewUploader(s.Client, func(u *manager.Uploader) {
+		// zero values fall back to the manager defaults
+		u.PartSize = s.PartSize
+		u.Concurrency = s.PartConcurrency
+		u.ClientOptions = optFns
+		// the manager aborts with the upload context which might already be cancelled, so abort ourselves
+		u.LeavePartsOnError = true
+	})
+	if _, err := uploader.Upload(ctx, params); err != nil {
+		var mf manager.MultiUploadFailure
+		if errors.As(err, &mf) {
+			s.abortMultipartUpload(params, mf.UploadID())
+		}
+		return err
+	}
+	return nil
+}
+
+// abortMultipartUpload aborts an incomplete multipart upload.
+func (s *SimpleStorageService) abortMultipartUpload(params *s3.PutObjectInput, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.AbortMultipartTimeout)
+	defer cancel()
+
+	if _, err := s.Client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   params.Bucket,
+		Key:      params.Key,
+		UploadId: aws.String(uploadID),
+	}); err != nil && s.EnableDebug {
+		log.Printf("Abort of multipart upload %s for %s failed with err: %s\n", uploadID, aws.ToString(params.Key), err.Error())
+	}
+}
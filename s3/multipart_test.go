@@ -0,0 +1,228 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	awsretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// fakeS3 is an in-memory S3 API serving PutObject, HeadObject and multipart uploads.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	parts   map[string][]byte
+	puts    int
+	uploads int
+	// failPart is the number of the part that fails to upload, if any.
+	failPart string
+	// aborted are the keys of the aborted multipart uploads by upload ID.
+	aborted map[string]string
+	// failPuts is the number of PutObject requests left to fail with a 503.
+	failPuts int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		f.uploads++
+		fmt.Fprintf(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>%s</Key><UploadId>upload-%d</UploadId></InitiateMultipartUploadResult>`, key, f.uploads)
+	case r.Method == http.MethodPut && query.Has("partNumber") && query.Get("partNumber") == f.failPart:
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Error><Code>InvalidPart</Code><Message>part failed</Message></Error>`)
+	case r.Method == http.MethodPut && query.Has("partNumber"):
+		body, _ := io.ReadAll(r.Body)
+		f.parts[query.Get("partNumber")] = body
+		w.Header().Set("ETag", `"etag-`+query.Get("partNumber")+`"`)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var body []byte
+		for i := 1; i <= len(f.parts); i++ {
+			body = append(body, f.parts[fmt.Sprint(i)]...)
+		}
+		f.objects[key] = body
+		fmt.Fprintf(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>%s</Key><ETag>"etag"</ETag></CompleteMultipartUploadResult>`, key)
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		f.aborted[query.Get("uploadId")] = key
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		f.puts++
+		if f.failPuts > 0 {
+			f.failPuts--
+			io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `<Error><Code>SlowDown</Code><Message>slow down</Message></Error>`)
+			return
+		}
+		f.objects[key], _ = io.ReadAll(r.Body)
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("ETag", `"etag"`)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+// newFakeS3 returns a S3 bridge talking to a fake S3 API.
+func newFakeS3(t *testing.T) (*SimpleStorageService, *fakeS3) {
+	fake := &fakeS3{objects: map[string][]byte{}, parts: map[string][]byte{}, aborted: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:           "us-east-1",
+		Credentials:      credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		EndpointResolver: s3.EndpointResolverFromURL(server.URL),
+		UsePathStyle:     true,
+		Retryer: awsretry.NewStandard(func(o *awsretry.StandardOptions) {
+			o.MaxAttempts = 3
+			o.Backoff = awsretry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+		}),
+	})
+	return &SimpleStorageService{
+		DefaultBucket:      "bucket",
+		Region:             "us-east-1",
+		DefaultTimeout:     10,
+		Client:             client,
+		MultipartThreshold: 10 << 20,
+		PartSize:           5 << 20,
+	}, fake
+}
+
+func TestUpload(t *testing.T) {
+	t.Run("Tests Upload method with a seekable handle", func(t *testing.T) {
+		s, fake := newFakeS3(t)
+
+		o, err := s.Upload(context.Background(), types.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fake.puts != 1 || fake.uploads != 0 {
+			t.Errorf("Expected a single PutObject, got %d puts and %d multipart uploads", fake.puts, fake.uploads)
+		}
+		if o.Size != 11 {
+			t.Errorf("Expected the size to come from HeadObject, got %d", o.Size)
+		}
+		if _, ok := o.ProviderObject.(*s3.HeadObjectOutput); !ok {
+			t.Errorf("Expected a HeadObjectOutput, got %T", o.ProviderObject)
+		}
+	})
+
+	t.Run("Tests Upload method streams a handle of unknown length", func(t *testing.T) {
+		s, fake := newFakeS3(t)
+		body := strings.Repeat("a", 6<<20)
+
+		o, err := s.Upload(context.Background(), types.File{
+			Handle:   io.MultiReader(strings.NewReader(body)),
+			Filename: "large.txt",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fake.uploads != 1 || len(fake.parts) != 2 {
+			t.Errorf("Expected a multipart upload of 2 parts, got %d uploads of %d parts", fake.uploads, len(fake.parts))
+		}
+		if string(fake.objects["bucket/large.txt"]) != body || o.Size != int64(len(body)) {
+			t.Errorf("Expected %d bytes to be stored, got %d", len(body), o.Size)
+		}
+	})
+
+	t.Run("Tests Upload method aborts a failed multipart upload", func(t *testing.T) {
+		s, fake := newFakeS3(t)
+		fake.failPart = "2"
+
+		if _, err := s.Upload(context.Background(), types.File{
+			Handle:   io.MultiReader(strings.NewReader(strings.Repeat("a", 11<<20))),
+			Filename: "large.txt",
+		}); err == nil {
+			t.Fatalf("Expected the upload to fail")
+		}
+		if len(fake.aborted) != 1 || fake.aborted["upload-1"] != "bucket/large.txt" {
+			t.Errorf("Expected upload-1 of bucket/large.txt to be aborted, got %v", fake.aborted)
+		}
+		if _, ok := fake.objects["bucket/large.txt"]; ok {
+			t.Errorf("Expected no object to be stored")
+		}
+	})
+
+	t.Run("Tests Upload method does not stack SDK retries on RetryPolicy", func(t *testing.T) {
+		s, fake := newFakeS3(t)
+		s.RetryPolicy = &types.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+		fake.failPuts = 5
+
+		if _, err := s.Upload(context.Background(), types.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		}); err == nil {
+			t.Fatalf("Expected the upload to fail")
+		}
+		if fake.puts != 2 {
+			t.Errorf("Expected RetryPolicy to make 2 attempts, got %d", fake.puts)
+		}
+	})
+
+	t.Run("Tests Upload method keeps SDK retries without RetryPolicy", func(t *testing.T) {
+		s, fake := newFakeS3(t)
+		fake.failPuts = 2
+
+		if _, err := s.Upload(context.Background(), types.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fake.puts != 3 {
+			t.Errorf("Expected the SDK to make 3 attempts, got %d", fake.puts)
+		}
+	})
+
+	t.Run("Tests Upload method honours the context", func(t *testing.T) {
+		s, _ := newFakeS3(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := s.Upload(ctx, types.File{
+			Handle:   strings.NewReader("hello world"),
+			Filename: "hello.txt",
+		}); err == nil {
+			t.Errorf("Expected the upload to fail with a cancelled context")
+		}
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("Tests Delete method without a Filename", func(t *testing.T) {
+		s, _ := newFakeS3(t)
+
+		err := s.Delete(context.Background(), types.DeleteFile{CID: "QmHash"})
+		if err == nil || err.(*errors.BifrostError).Code() != errors.ErrInvalidParameters {
+			t.Errorf("Expected %s error, got %v", errors.ErrInvalidParameters, err)
+		}
+	})
+}
@@ -4,17 +4,28 @@ package s3
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	awsTypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/opensaucerer/bifrost/shared/config"
 	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/pool"
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/stream"
 	"github.com/opensaucerer/bifrost/shared/types"
+	"github.com/opensaucerer/bifrost/shared/walk"
 )
 
 /*
@@ -23,16 +34,52 @@ UploadFile uploads a file to S3 and returns an error if one occurs.
 Note: UploadFile requires that a default bucket be set in bifrost.BridgeConfig.
 */
 func (s *SimpleStorageService) UploadFile(fileFace interface{}) (*types.UploadedFile, error) {
+	return s.UploadFileContext(context.Background(), fileFace)
+}
+
+// UploadFileContext is like UploadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (s *SimpleStorageService) UploadFileContext(ctx context.Context, fileFace interface{}) (*types.UploadedFile, error) {
+	bFile, err := types.AsFile(fileFace)
+	if err != nil {
+		return nil, err
+	}
+	return s.Upload(ctx, bFile)
+}
+
+// Upload uploads a file to S3 and returns an error if one occurs. It is the typed equivalent of UploadFileContext.
+// Uploads failing with a transient error are retried according to the bridge RetryPolicy.
+func (s *SimpleStorageService) Upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
+	var uploadedFile *types.UploadedFile
+	err := retry.Upload(ctx, s.RetryPolicy, s.IsRetryable, bFile, func() error {
+		var err error
+		uploadedFile, err = s.upload(ctx, bFile)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uploadedFile, nil
+}
+
+/*
+UploadJSON marshals doc.Data and uploads it to S3 as a JSON document with the application/json content type, unless
+another content type is set in doc.Options, and returns an error if one occurs.
 
-	// assert that the fileFace is of type bifrost.File
-	bFile, ok := fileFace.(types.File)
-	if !ok {
+Note: UploadJSON requires doc.Filename.
+*/
+func (s *SimpleStorageService) UploadJSON(ctx context.Context, doc types.JSONFile) (*types.UploadedFile, error) {
+	bFile, err := doc.File()
+	if err != nil {
 		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("argument must be of type bifrost.File"),
-			ErrorCode: errors.ErrBadRequest,
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
 		}
 	}
+	return s.Upload(ctx, bFile)
+}
 
+// upload makes a single attempt at uploading a file to S3.
+func (s *SimpleStorageService) upload(ctx context.Context, bFile types.File) (*types.UploadedFile, error) {
 	// validate struct
 	if err := bFile.Validate(); err != nil {
 		return nil, &errors.BifrostError{
@@ -47,9 +94,7 @@ func (s *SimpleStorageService) UploadFile(fileFace interface{}) (*types.Uploaded
 			ErrorCode: errors.ErrClientError,
 		}
 	}
-	var ctx context.Context
 	var cancel context.CancelFunc
-	ctx = context.Background()
 	if s.DefaultTimeout > 0 {
 		ctx, cancel = context.WithTimeout(ctx, time.Duration(s.DefaultTimeout)*time.Second)
 		defer cancel()
@@ -85,8 +130,12 @@ func (s *SimpleStorageService) UploadFile(fileFace interface{}) (*types.Uploaded
 	var params *s3.PutObjectInput = &s3.PutObjectInput{
 		Bucket: aws.String(s.DefaultBucket),
 		Key:    aws.String(bFile.Filename),
-		Body:   bFile.Handle,
+		// report progress as the SDK reads the body
+		Body: progress.Reader(bFile.Handle, bFile),
 	}
+	// work on a copy of the options so that the caller's map is never written to
+	bFile = bFile.Clone()
+
 	// if no ACL is set, check if w.PublicRead is true
 	if bFile.Options[config.OptACL] == nil && s.PublicRead {
 		// set public read permissions
@@ -118,8 +167,8 @@ func (s *SimpleStorageService) UploadFile(fileFace interface{}) (*types.Uploaded
 			}
 		}
 	}
-	// Upload the file to S3
-	if _, err := s.Client.PutObject(ctx, params); err != nil {
+	// Upload the file to S3, in parts if it is large or of unknown length
+	if err := s.putObject(ctx, params); err != nil {
 		return nil, &errors.BifrostError{
 			Err:       err,
 			ErrorCode: errors.ErrFileOperationFailed,
@@ -140,25 +189,29 @@ func (s *SimpleStorageService) UploadFile(fileFace interface{}) (*types.Uploaded
 		Name:           bFile.Filename,
 		Bucket:         s.DefaultBucket,
 		Path:           bFile.Path,
-		Preview:        fmt.Sprintf(config.URLSimpleStorageService, s.DefaultBucket, s.Region, bFile.Filename),
+		Preview:        s.objectURL(s.DefaultBucket, bFile.Filename),
 		Size:           obj.ContentLength,
 		ProviderObject: obj,
-		URL:            fmt.Sprintf(config.URLSimpleStorageService, s.DefaultBucket, s.Region, bFile.Filename),
+		URL:            s.objectURL(s.DefaultBucket, bFile.Filename),
 	}, nil
 }
 
 // UploadMultiFile
 func (s *SimpleStorageService) UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error) {
+	return s.UploadMultiFileContext(context.Background(), multiFace)
+}
 
-	// assert that the multiFace is of type bifrost.File
-	multiFile, ok := multiFace.(types.MultiFile)
-	if !ok {
-		return nil, &errors.BifrostError{
-			Err:       fmt.Errorf("argument must be of type bifrost.MultiFile"),
-			ErrorCode: errors.ErrBadRequest,
-		}
+// UploadMultiFileContext is like UploadMultiFile but uses ctx for cancellation and deadlines of every upload.
+func (s *SimpleStorageService) UploadMultiFileContext(ctx context.Context, multiFace interface{}) ([]*types.UploadedFile, error) {
+	multiFile, err := types.AsMultiFile(multiFace)
+	if err != nil {
+		return nil, err
 	}
+	return s.UploadMulti(ctx, multiFile)
+}
 
+// UploadMulti uploads multiple files to S3 and returns an error if one occurs. It is the typed equivalent of UploadMultiFileContext.
+func (s *SimpleStorageService) UploadMulti(ctx context.Context, multiFile types.MultiFile) ([]*types.UploadedFile, error) {
 	// validate struct
 	if err := multiFile.Validate(); err != nil {
 		return nil, &errors.BifrostError{
@@ -174,31 +227,26 @@ func (s *SimpleStorageService) UploadMultiFile(multiFace interface{}) ([]*types.
 		}
 	}
 
-	uploadedFiles := make([]*types.UploadedFile, 0, len(multiFile.Files))
+	uploadedFiles := make([]*types.UploadedFile, len(multiFile.Files))
+	batch := progress.NewBatch(multiFile)
 
-	// @TODO: add concurrency when UseAsync is true
-	for _, file := range multiFile.Files {
-		if multiFile.GlobalOptions != nil {
-			// merge global options with file options
-			for k, v := range multiFile.GlobalOptions {
-				// don't override if file has option already
-				if _, ok := file.Options[k]; !ok {
-					file.Options[k] = v
-				}
-			}
-		}
+	// upload concurrently when UseAsync is true, writing each result at its file's index to keep the order of multiFile.Files
+	pool.Run(len(multiFile.Files), pool.Size(s.UseAsync, s.MaxConcurrency), func(i int) {
+		// merge global options into a copy of the file options so that shared maps are never written to
+		file := batch.Track(i, multiFile.Merge(i))
 
-		uploadedFile, err := s.UploadFile(file)
+		uploadedFile, err := s.Upload(ctx, file)
+		batch.Done(i, err)
 		if err != nil {
 			if s.EnableDebug {
 				// log failed file and continue
 				log.Printf("Upload for file at path %s failed with err: %s\n", file.Path, err.Error())
 			}
-			uploadedFiles = append(uploadedFiles, &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path})
-			continue
+			uploadedFiles[i] = &types.UploadedFile{Error: err, Name: file.Filename, Path: file.Path}
+			return
 		}
-		uploadedFiles = append(uploadedFiles, uploadedFile)
-	}
+		uploadedFiles[i] = uploadedFile
+	})
 
 	return uploadedFiles, nil
 }
@@ -206,14 +254,22 @@ func (s *SimpleStorageService) UploadMultiFile(multiFace interface{}) ([]*types.
 // Config returns the s3 configuration.
 func (s *SimpleStorageService) Config() *types.BridgeConfig {
 	return &types.BridgeConfig{
-		DefaultBucket:  s.DefaultBucket,
-		Region:         s.Region,
-		AccessKey:      s.AccessKey,
-		SecretKey:      s.SecretKey,
-		DefaultTimeout: s.DefaultTimeout,
-		EnableDebug:    s.EnableDebug,
-		Provider:       s.Provider,
-		UseAsync:       s.UseAsync,
+		DefaultBucket:      s.DefaultBucket,
+		Region:             s.Region,
+		Endpoint:           s.Endpoint,
+		ForcePathStyle:     s.ForcePathStyle,
+		BaseURL:            s.BaseURL,
+		AccessKey:          s.AccessKey,
+		SecretKey:          s.SecretKey,
+		DefaultTimeout:     s.DefaultTimeout,
+		EnableDebug:        s.EnableDebug,
+		Provider:           s.Provider,
+		UseAsync:           s.UseAsync,
+		MaxConcurrency:     s.MaxConcurrency,
+		RetryPolicy:        s.RetryPolicy,
+		MultipartThreshold: s.MultipartThreshold,
+		PartSize:           s.PartSize,
+		PartConcurrency:    s.PartConcurrency,
 	}
 }
 
@@ -234,20 +290,473 @@ func (s *SimpleStorageService) IsConnected() bool {
 	return s.Client != nil
 }
 
+// Ping checks that the default S3 bucket can be reached with the configured credentials and returns an error if one occurs.
+func (s *SimpleStorageService) Ping(ctx context.Context) error {
+	if !s.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active S3 client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if s.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(s.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	if _, err := s.Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.DefaultBucket),
+	}); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	return nil
+}
+
 /*
-UploadFolder uploads a folder to the provider storage and returns an error if one occurs.
+UploadFolder uploads every file in a local folder to S3 and returns an error if one occurs.
+Each file is stored under its path relative to Folder.Path, behind Folder.Prefix, and Folder.Options are merged into
+each file's options the same way as MultiFile.GlobalOptions. Like UploadMultiFile, one UploadedFile is returned per file with
+any failure recorded in its Error field.
 
-Note: for some providers, UploadFolder requires that a default bucket be set in bifrost.BridgeConfig.
+Note: UploadFolder requires that a default bucket be set in bifrost.BridgeConfig.
 */
 func (s *SimpleStorageService) UploadFolder(foldFace interface{}) ([]*types.UploadedFile, error) {
-	return nil, nil
+	return s.UploadFolderContext(context.Background(), foldFace)
+}
+
+// UploadFolderContext is like UploadFolder but uses ctx for cancellation and deadlines of every upload.
+func (s *SimpleStorageService) UploadFolderContext(ctx context.Context, foldFace interface{}) ([]*types.UploadedFile, error) {
+	folder, err := types.AsFolder(foldFace)
+	if err != nil {
+		return nil, err
+	}
+	return s.UploadDir(ctx, folder)
+}
+
+// UploadDir uploads every file in a local folder to S3 and returns an error if one occurs. It is the typed equivalent of UploadFolderContext.
+func (s *SimpleStorageService) UploadDir(ctx context.Context, folder types.Folder) ([]*types.UploadedFile, error) {
+	// validate struct
+	if err := folder.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	files, err := walk.Files(folder)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	if len(files) == 0 {
+		return []*types.UploadedFile{}, nil
+	}
+
+	return s.UploadMulti(ctx, types.MultiFile{
+		Files:         files,
+		GlobalOptions: folder.Options,
+	})
 }
 
 /*
 DeleteFile deletes a file from S3 and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
 
-Note: DeleteFile requires that a default bucket be set in bifrost.BridgeConfig.
+Note: DeleteFile requires that a default bucket be set in bifrost.BridgeConfig unless DeleteFile.Buckets is set.
 */
 func (s *SimpleStorageService) DeleteFile(fileFace interface{}) error {
+	return s.DeleteFileContext(context.Background(), fileFace)
+}
+
+// DeleteFileContext is like DeleteFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (s *SimpleStorageService) DeleteFileContext(ctx context.Context, fileFace interface{}) error {
+	dFile, err := types.AsDeleteFile(fileFace)
+	if err != nil {
+		return err
+	}
+	return s.Delete(ctx, dFile)
+}
+
+// Delete deletes a file from S3 and returns an error if one occurs. It is the typed equivalent of DeleteFileContext.
+func (s *SimpleStorageService) Delete(ctx context.Context, dFile types.DeleteFile) error {
+	// validate struct
+	if err := dFile.Validate(); err != nil {
+		return &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+	// files are stored by name, so a CID alone does not identify one
+	if dFile.Filename == "" {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("file.Filename is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !s.IsConnected() {
+		return &errors.BifrostError{
+			Err:       fmt.Errorf("no active S3 client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	var cancel context.CancelFunc
+	if s.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(s.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	buckets := dFile.Buckets
+	if len(buckets) == 0 {
+		buckets = []string{s.DefaultBucket}
+	}
+
+	for _, bucket := range buckets {
+		// S3 reports success when deleting a missing key, so check that the object exists first
+		if _, err := s.Client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(dFile.Filename),
+		}); err != nil {
+			if isNotFound(err) {
+				return &errors.BifrostError{
+					Err:       fmt.Errorf("file does not exist: %s/%s", bucket, dFile.Filename),
+					ErrorCode: errors.ErrNotFound,
+				}
+			}
+			return &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+
+		if _, err := s.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(dFile.Filename),
+		}); err != nil {
+			return &errors.BifrostError{
+				Err:       err,
+				ErrorCode: errors.ErrFileOperationFailed,
+			}
+		}
+	}
 	return nil
 }
+
+/*
+OpenReader opens a file in S3 for reading and returns its contents along with its attributes.
+If the file does not exist, an error with the code ErrNotFound is returned.
+The caller must close the returned reader.
+
+Note: OpenReader requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (s *SimpleStorageService) OpenReader(name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	return s.OpenReaderContext(context.Background(), name)
+}
+
+// OpenReaderContext is like OpenReader but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx
+// and covers reading the returned reader.
+func (s *SimpleStorageService) OpenReaderContext(ctx context.Context, name string) (io.ReadCloser, *types.ObjectInfo, error) {
+	if name == "" {
+		return nil, nil, &errors.BifrostError{
+			Err:       fmt.Errorf("name is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !s.IsConnected() {
+		return nil, nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active S3 client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// the context must outlive this call as the body is read by the caller, so it is cancelled when the reader is closed
+	var cancel context.CancelFunc
+	if s.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(s.DefaultTimeout)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	obj, err := s.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.DefaultBucket),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		cancel()
+		if isNotFound(err) {
+			return nil, nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s/%s", s.DefaultBucket, name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	return stream.CancelOnClose(obj.Body, cancel), &types.ObjectInfo{
+		Name:           name,
+		Bucket:         s.DefaultBucket,
+		Size:           obj.ContentLength,
+		ContentType:    aws.ToString(obj.ContentType),
+		Metadata:       obj.Metadata,
+		ETag:           strings.Trim(aws.ToString(obj.ETag), `"`),
+		Updated:        aws.ToTime(obj.LastModified),
+		URL:            s.objectURL(s.DefaultBucket, name),
+		ProviderObject: obj,
+	}, nil
+}
+
+/*
+DownloadFile downloads a file from S3 to localPath and returns its attributes.
+localPath is only replaced once the whole file has been downloaded.
+
+Note: DownloadFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (s *SimpleStorageService) DownloadFile(name, localPath string) (*types.ObjectInfo, error) {
+	return s.DownloadFileContext(context.Background(), name, localPath)
+}
+
+// DownloadFileContext is like DownloadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (s *SimpleStorageService) DownloadFileContext(ctx context.Context, name, localPath string) (*types.ObjectInfo, error) {
+	if localPath == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("localPath is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	rc, info, err := s.OpenReaderContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if info.Size, err = stream.SaveFile(localPath, rc); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return info, nil
+}
+
+/*
+ListFiles lists the files in the default S3 bucket one page at a time and returns an error if one occurs.
+Pass ListResult.NextToken as ListOptions.ContinuationToken to list the following page.
+
+Note: ListFiles requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (s *SimpleStorageService) ListFiles(opts types.ListOptions) (*types.ListResult, error) {
+	return s.ListFilesContext(context.Background(), opts)
+}
+
+// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (s *SimpleStorageService) ListFilesContext(ctx context.Context, opts types.ListOptions) (*types.ListResult, error) {
+
+	// validate struct
+	if err := opts.Validate(); err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !s.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active S3 client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if s.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(s.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	params := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.DefaultBucket),
+	}
+	if opts.Prefix != "" {
+		params.Prefix = aws.String(opts.Prefix)
+	}
+	if opts.Delimiter != "" {
+		params.Delimiter = aws.String(opts.Delimiter)
+	}
+	if opts.ContinuationToken != "" {
+		params.ContinuationToken = aws.String(opts.ContinuationToken)
+	}
+	if opts.PageSize > 0 {
+		params.MaxKeys = int32(opts.PageSize)
+	}
+
+	out, err := s.Client.ListObjectsV2(ctx, params)
+	if err != nil {
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	result := &types.ListResult{
+		Files: make([]*types.ObjectInfo, 0, len(out.Contents)),
+	}
+	for _, obj := range out.Contents {
+		result.Files = append(result.Files, &types.ObjectInfo{
+			Name:           aws.ToString(obj.Key),
+			Bucket:         s.DefaultBucket,
+			Size:           obj.Size,
+			ETag:           strings.Trim(aws.ToString(obj.ETag), `"`),
+			Updated:        aws.ToTime(obj.LastModified),
+			URL:            s.objectURL(s.DefaultBucket, aws.ToString(obj.Key)),
+			ProviderObject: obj,
+		})
+	}
+	for _, prefix := range out.CommonPrefixes {
+		result.Prefixes = append(result.Prefixes, aws.ToString(prefix.Prefix))
+	}
+	if out.IsTruncated {
+		result.NextToken = aws.ToString(out.NextContinuationToken)
+	}
+	return result, nil
+}
+
+/*
+StatFile returns the attributes of a file in the default S3 bucket and returns an error if one occurs.
+If the file does not exist, an error with the code ErrNotFound is returned.
+
+Note: StatFile requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (s *SimpleStorageService) StatFile(name string) (*types.ObjectInfo, error) {
+	return s.StatFileContext(context.Background(), name)
+}
+
+// StatFileContext is like StatFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (s *SimpleStorageService) StatFileContext(ctx context.Context, name string) (*types.ObjectInfo, error) {
+	if name == "" {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("name is required"),
+			ErrorCode: errors.ErrInvalidParameters,
+		}
+	}
+
+	if !s.IsConnected() {
+		return nil, &errors.BifrostError{
+			Err:       fmt.Errorf("no active S3 client"),
+			ErrorCode: errors.ErrClientError,
+		}
+	}
+	// add timeout if default timeout is set
+	var cancel context.CancelFunc
+	if s.DefaultTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(s.DefaultTimeout)*time.Second)
+		defer cancel()
+	}
+
+	obj, err := s.Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.DefaultBucket),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, &errors.BifrostError{
+				Err:       fmt.Errorf("file does not exist: %s/%s", s.DefaultBucket, name),
+				ErrorCode: errors.ErrNotFound,
+			}
+		}
+		return nil, &errors.BifrostError{
+			Err:       err,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+
+	return &types.ObjectInfo{
+		Name:           name,
+		Bucket:         s.DefaultBucket,
+		Size:           obj.ContentLength,
+		ContentType:    aws.ToString(obj.ContentType),
+		Metadata:       obj.Metadata,
+		ETag:           strings.Trim(aws.ToString(obj.ETag), `"`),
+		Updated:        aws.ToTime(obj.LastModified),
+		URL:            s.objectURL(s.DefaultBucket, name),
+		ProviderObject: obj,
+	}, nil
+}
+
+/*
+Exists returns true if a file exists in the default S3 bucket and returns an error if the lookup fails.
+
+Note: Exists requires that a default bucket be set in bifrost.BridgeConfig.
+*/
+func (s *SimpleStorageService) Exists(name string) (bool, error) {
+	return s.ExistsContext(context.Background(), name)
+}
+
+// ExistsContext is like Exists but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+func (s *SimpleStorageService) ExistsContext(ctx context.Context, name string) (bool, error) {
+	if _, err := s.StatFileContext(ctx, name); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// isNotFound returns true if err is an S3 response error with a 404 status code.
+func isNotFound(err error) bool {
+	var re *awshttp.ResponseError
+	return errors.As(err, &re) && re.HTTPStatusCode() == http.StatusNotFound
+}
+
+// IsRetryable returns true if err is a transient S3 failure, such as a throttled request, a 5xx response or a dropped
+// connection, that is worth retrying.
+func (s *SimpleStorageService) IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if awsretry.IsErrorRetryables(awsretry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary {
+		return true
+	}
+	var re *awshttp.ResponseError
+	if errors.As(err, &re) {
+		return retry.IsRetryableStatus(re.HTTPStatusCode())
+	}
+	return retry.IsTransient(err)
+}
+
+/*
+objectURL returns the public URL of the object stored as key in bucket.
+
+The URL is built from BaseURL when it is set, then from the endpoint of an S3-compatible service, using path-style
+addressing if ForcePathStyle is set, and falls back to the AWS virtual-hosted URL.
+*/
+func (s *SimpleStorageService) objectURL(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	path := strings.Join(segments, "/")
+
+	if s.BaseURL != "" {
+		return strings.TrimSuffix(s.BaseURL, "/") + "/" + path
+	}
+	if s.Endpoint != "" {
+		u, err := url.Parse(s.Endpoint)
+		if err == nil && u.Host != "" {
+			prefix := strings.TrimSuffix(u.Path, "/")
+			if s.ForcePathStyle {
+				return fmt.Sprintf("%s://%s%s/%s/%s", u.Scheme, u.Host, prefix, url.PathEscape(bucket), path)
+			}
+			return fmt.Sprintf("%s://%s.%s%s/%s", u.Scheme, bucket, u.Host, prefix, path)
+		}
+	}
+	return fmt.Sprintf(config.URLSimpleStorageService, bucket, s.Region, path)
+}
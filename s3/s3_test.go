@@ -1,7 +1,10 @@
 package s3_test
 
 import (
+	"context"
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/opensaucerer/bifrost"
@@ -14,6 +17,9 @@ var (
 	AWS_ACCESS_KEY_ID     = os.Getenv("AWS_ACCESS_KEY")
 	AWS_SECRET_ACCESS_KEY = os.Getenv("AWS_SECRET_KEY")
 	AWS_BUCKET_NAME       = os.Getenv("AWS_BUCKET_NAME")
+	// set these to run the tests against an S3-compatible service such as a local MinIO
+	AWS_ENDPOINT         = os.Getenv("AWS_ENDPOINT")
+	AWS_FORCE_PATH_STYLE = os.Getenv("AWS_FORCE_PATH_STYLE") == "true"
 )
 
 func setup(t *testing.T) {
@@ -27,6 +33,8 @@ func setup(t *testing.T) {
 		AccessKey:      AWS_ACCESS_KEY_ID,
 		SecretKey:      AWS_SECRET_ACCESS_KEY,
 		Region:         "ap-northeast-1",
+		Endpoint:       AWS_ENDPOINT,
+		ForcePathStyle: AWS_FORCE_PATH_STYLE,
 	})
 	if err != nil {
 		t.Error(err.(bifrost.Error).Code(), err)
@@ -61,6 +69,65 @@ func TestS3(t *testing.T) {
 		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
 	})
 
+	t.Run("Tests Upload method", func(t *testing.T) {
+		o, err := bridge.Upload(context.Background(), bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+		})
+		if err != nil {
+			t.Errorf("Failed to upload file: %v", err)
+			return
+		}
+		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
+
+		// the untyped methods also accept pointers
+		if _, err := bridge.UploadFile(&bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+		}); err != nil {
+			t.Errorf("Failed to upload file from pointer: %v", err)
+		}
+	})
+
+	t.Run("Tests UploadFile method with a non-seekable handle", func(t *testing.T) {
+		f, err := os.Open("../shared/image/aand.png")
+		if err != nil {
+			t.Errorf("Failed to open file: %v", err)
+			return
+		}
+		defer f.Close()
+
+		// a reader of unknown length is streamed as a multipart upload
+		o, err := bridge.UploadFile(bifrost.File{
+			Handle:   io.MultiReader(f),
+			Filename: "a_and_ampersand_stream.png",
+		})
+		if err != nil {
+			t.Errorf("Failed to upload file: %v", err)
+			return
+		}
+		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
+	})
+
+	t.Run("Tests UploadFile method with progress reporting", func(t *testing.T) {
+		var last bifrost.Progress
+		o, err := bridge.UploadFile(bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+			Progress: func(p bifrost.Progress) {
+				last = p
+			},
+		})
+		if err != nil {
+			t.Errorf("Failed to upload file: %v", err)
+			return
+		}
+		if last.Total <= 0 || last.Bytes != last.Total {
+			t.Errorf("Expected progress to reach the file size, got %d of %d bytes", last.Bytes, last.Total)
+		}
+		t.Logf("Uploaded file: %s to %s\n", o.Name, o.Preview)
+	})
+
 	t.Run("Tests UploadMultiFile method", func(t *testing.T) {
 		o, err := bridge.UploadMultiFile(bifrost.MultiFile{
 			Files: []bifrost.File{
@@ -101,4 +168,116 @@ func TestS3(t *testing.T) {
 		}
 	})
 
+	t.Run("Tests UploadFolder method", func(t *testing.T) {
+		o, err := bridge.UploadFolder(bifrost.Folder{
+			Path:    "../shared/image",
+			Prefix:  "bifrost/images/",
+			Exclude: []string{"*.webp"},
+			Options: map[string]interface{}{
+				bifrost.OptMetadata: map[string]string{
+					"folder": "image",
+				},
+			},
+		})
+		if err != nil {
+			t.Errorf("Failed to upload folder: %v", err)
+			return
+		}
+
+		for _, file := range o {
+			if file.Error != nil {
+				t.Errorf("Failed to upload file %s: %v", file.Name, file.Error)
+				continue
+			}
+			t.Logf("Uploaded file: %s to %s\n", file.Name, file.Preview)
+		}
+	})
+
+	t.Run("Tests UploadFileContext method with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := bridge.UploadFileContext(ctx, bifrost.File{
+			Path:     "../shared/image/aand.png",
+			Filename: "a_and_ampersand.png",
+		})
+		if err == nil {
+			t.Errorf("Expected upload with a cancelled context to fail")
+			return
+		}
+		t.Logf("Upload with a cancelled context failed with: %v\n", err)
+	})
+
+	t.Run("Tests DownloadFile method", func(t *testing.T) {
+		localPath := filepath.Join(t.TempDir(), "a_and_ampersand.png")
+		o, err := bridge.DownloadFile("a_and_ampersand.png", localPath)
+		if err != nil {
+			t.Errorf("Failed to download file: %v", err)
+			return
+		}
+		t.Logf("Downloaded file: %s (%d bytes) to %s\n", o.Name, o.Size, localPath)
+	})
+
+	t.Run("Tests ListFiles method", func(t *testing.T) {
+		opts := bifrost.ListOptions{
+			Prefix:   "bifrost/",
+			PageSize: 1,
+		}
+		for {
+			o, err := bridge.ListFiles(opts)
+			if err != nil {
+				t.Errorf("Failed to list files: %v", err)
+				return
+			}
+			for _, file := range o.Files {
+				t.Logf("Listed file: %s (%d bytes)\n", file.Name, file.Size)
+			}
+			if o.NextToken == "" {
+				break
+			}
+			opts.ContinuationToken = o.NextToken
+		}
+	})
+
+	t.Run("Tests StatFile and Exists methods", func(t *testing.T) {
+		o, err := bridge.StatFile("a_and_ampersand.png")
+		if err != nil {
+			t.Errorf("Failed to stat file: %v", err)
+			return
+		}
+		t.Logf("Stat file: %s (%d bytes, %s)\n", o.Name, o.Size, o.ContentType)
+
+		exists, err := bridge.Exists("does_not_exist.png")
+		if err != nil {
+			t.Errorf("Failed to check file: %v", err)
+			return
+		}
+		if exists {
+			t.Errorf("Expected does_not_exist.png not to exist")
+		}
+
+		if _, err := bridge.StatFile("does_not_exist.png"); !bifrost.IsNotFound(err) {
+			t.Errorf("Expected %s error, got: %v", bifrost.ErrNotFound, err)
+		}
+	})
+
+	t.Run("Tests DeleteFile method", func(t *testing.T) {
+		if err := bridge.DeleteFile(bifrost.DeleteFile{
+			Filename: "bifrost_bridge.webp",
+		}); err != nil {
+			t.Errorf("Failed to delete file: %v", err)
+			return
+		}
+
+		// deleting the same file again should report that it does not exist
+		err := bridge.DeleteFile(bifrost.DeleteFile{
+			Filename: "bifrost_bridge.webp",
+		})
+		if err == nil || err.(bifrost.Error).Code() != bifrost.ErrNotFound {
+			t.Errorf("Expected %s error, got: %v", bifrost.ErrNotFound, err)
+			return
+		}
+		t.Logf("Deleted file: %s\n", "bifrost_bridge.webp")
+	})
+
 }
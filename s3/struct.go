@@ -15,12 +15,28 @@ type SimpleStorageService struct {
 	CredentialsFile string
 	// Region is the S3 region to use for storage
 	Region string
+	// Endpoint is the URL of the S3-compatible service in use. AWS is used when it is empty.
+	Endpoint string
+	// ForcePathStyle addresses buckets in the URL path instead of the host name.
+	ForcePathStyle bool
+	// BaseURL is the URL DefaultBucket is served from, used to build the URL of uploaded files.
+	BaseURL string
 	// Zone is the S3 zone to use for storage
 	Zone string
 	// DefaultTimeout is the time-to-live for time-dependent S3 operations
 	DefaultTimeout int64
 	// UseAsync enables asynchronous operations with go routines.
 	UseAsync bool
+	// MaxConcurrency is the maximum number of concurrent uploads when UseAsync is enabled.
+	MaxConcurrency int
+	// RetryPolicy configures how failed uploads are retried. Uploads are not retried when it is nil.
+	RetryPolicy *types.RetryPolicy
+	// MultipartThreshold is the size in bytes from which uploads are split into a multipart upload.
+	MultipartThreshold int64
+	// PartSize is the size in bytes of each part of a multipart upload.
+	PartSize int64
+	// PartConcurrency is the number of parts of a multipart upload that are uploaded at once.
+	PartConcurrency int
 	// s3 client
 	Client *s3.Client
 	// PublicRead enables public read access to uploaded files.
@@ -0,0 +1,39 @@
+package s3
+
+import "testing"
+
+func TestObjectURL(t *testing.T) {
+	cases := []struct {
+		name string
+		s    SimpleStorageService
+		want string
+	}{
+		{
+			name: "AWS",
+			s:    SimpleStorageService{Region: "ap-northeast-1"},
+			want: "https://bucket.s3.ap-northeast-1.amazonaws.com/dir/a%20b.png",
+		},
+		{
+			name: "path-style endpoint",
+			s:    SimpleStorageService{Endpoint: "http://localhost:9000", ForcePathStyle: true},
+			want: "http://localhost:9000/bucket/dir/a%20b.png",
+		},
+		{
+			name: "virtual-hosted endpoint",
+			s:    SimpleStorageService{Endpoint: "https://nyc3.digitaloceanspaces.com"},
+			want: "https://bucket.nyc3.digitaloceanspaces.com/dir/a%20b.png",
+		},
+		{
+			name: "base URL",
+			s:    SimpleStorageService{Endpoint: "https://account.r2.cloudflarestorage.com", BaseURL: "https://pub-123.r2.dev/"},
+			want: "https://pub-123.r2.dev/dir/a%20b.png",
+		},
+	}
+	for _, c := range cases {
+		t.Run("Tests objectURL with "+c.name, func(t *testing.T) {
+			if got := c.s.objectURL("bucket", "dir/a b.png"); got != c.want {
+				t.Errorf("Expected %s, got %s", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+// Default constants
+const (
+	// DefaultMaxConcurrency is the number of concurrent operations used when UseAsync is enabled and no MaxConcurrency is set.
+	DefaultMaxConcurrency = 10
+
+	// DefaultPageSize is the number of files listed per page for providers that require a page size when none is set.
+	DefaultPageSize = 1000
+
+	// DefaultMultipartThreshold is the size in bytes from which uploads are split into a multipart upload when no MultipartThreshold is set.
+	DefaultMultipartThreshold = 100 << 20
+
+	// DefaultRetryBaseDelay is the delay before the first retry when a RetryPolicy sets no BaseDelay.
+	DefaultRetryBaseDelay = 200 * time.Millisecond
+
+	// DefaultRetryMaxDelay is the longest delay between retries when a RetryPolicy sets no MaxDelay.
+	DefaultRetryMaxDelay = 10 * time.Second
+
+	// DefaultS3CompatibleRegion is the region requests to an S3-compatible endpoint are signed with when no region is set.
+	DefaultS3CompatibleRegion = "us-east-1"
+
+	// DefaultPinPollInterval is the delay between two polls of a Pinata pin job when no PinPollInterval is set.
+	DefaultPinPollInterval = 2 * time.Second
+
+	// AbortMultipartTimeout is the time allowed for cleaning up a failed multipart upload.
+	AbortMultipartTimeout = 30 * time.Second
+)
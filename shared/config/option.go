@@ -19,4 +19,7 @@ const (
 
 	// OptPinata is the option to set the pinataOptions
 	OptPinata = "pinataOptions"
+
+	// OptPinataMetadata is the option to set the pinataMetadata
+	OptPinataMetadata = "pinataMetadata"
 )
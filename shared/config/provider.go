@@ -14,4 +14,13 @@ const (
 
 	// WasabiCloudStorage is the identifier of the Wasabi Cloud Storage provider
 	WasabiCloudStorage = "wasabi"
+
+	// LocalStorage is the identifier of the local filesystem provider
+	LocalStorage = "local"
+
+	// MemoryStorage is the identifier of the in-memory provider
+	MemoryStorage = "memory"
+
+	// AzureBlobStorage is the identifier of the Azure Blob Storage provider
+	AzureBlobStorage = "azure"
 )
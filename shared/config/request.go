@@ -8,9 +8,18 @@ const (
 	// ReqBearer is the bearer token identifier
 	ReqBearer = "Bearer %s"
 
+	// ReqPinataAPIKey is the Pinata API key header identifier
+	ReqPinataAPIKey = "pinata_api_key"
+
+	// ReqPinataSecretAPIKey is the Pinata API secret header identifier
+	ReqPinataSecretAPIKey = "pinata_secret_api_key"
+
 	// ReqContentType is the content type header identifier
 	ReqContentType = "Content-Type"
 
+	// ReqJSON is the content type of JSON documents
+	ReqJSON = "application/json"
+
 	// MethodGet is the HTTP method for GET requests.
 	MethodGet = "GET"
 
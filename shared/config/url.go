@@ -11,6 +11,15 @@ const (
 	// URLPinataPinCID is the endpoint for pinning CIDs to Pinata cloud.
 	URLPinataPinCID = "https://api.pinata.cloud/pinning/pinByHash"
 
+	// URLPinataPinJobs is the endpoint for listing the queued pin by CID jobs of a Pinata cloud account.
+	URLPinataPinJobs = "https://api.pinata.cloud/pinning/pinJobs"
+
+	// URLPinataUnpin is the endpoint for unpinning CIDs from Pinata cloud.
+	URLPinataUnpin = "https://api.pinata.cloud/pinning/unpin/%s"
+
+	// URLPinataPinList is the endpoint for listing the pins of a Pinata cloud account.
+	URLPinataPinList = "https://api.pinata.cloud/data/pinList"
+
 	// URLPinataAuth is the endpoint for testing authentication against provided Pinata credentials
 	URLPinataAuth = "https://api.pinata.cloud/data/testAuthentication"
 
@@ -28,4 +37,7 @@ const (
 
 	// URLWasabiEndpoint is the endpoint for Wasabi Cloud Storage.
 	URLWasabiEndpoint = "https://s3.%s.wasabisys.com"
+
+	// URLAzureBlobStorage is the blob service endpoint of an Azure storage account.
+	URLAzureBlobStorage = "https://%s.blob.core.windows.net"
 )
@@ -31,4 +31,10 @@ const (
 
 	// ErrInvalidParameters is returned when the parameters are invalid.
 	ErrInvalidParameters = "invalid parameters"
+
+	// ErrNotFound is returned when the requested file does not exist with the provider.
+	ErrNotFound = "not found"
+
+	// ErrIntegrity is returned when a file stored with the provider does not match the file that was sent.
+	ErrIntegrity = "integrity check failed"
 )
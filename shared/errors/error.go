@@ -6,8 +6,19 @@ func (e *BifrostError) Error() string {
 	return e.Err.Error()
 }
 
+// Unwrap returns the underlying error so that errors.Is and errors.As can inspect it.
+func (e *BifrostError) Unwrap() error {
+	return e.Err
+}
+
 // Code returns the error code.
 func (e *BifrostError) Code() string {
 	// return the error code
 	return e.ErrorCode
 }
+
+// IsNotFound returns true if err is a *BifrostError with the code ErrNotFound.
+func IsNotFound(err error) bool {
+	var be *BifrostError
+	return As(err, &be) && be.ErrorCode == ErrNotFound
+}
@@ -0,0 +1,15 @@
+package errors
+
+import goerrors "errors"
+
+// Is reports whether any error in err's chain matches target.
+// It is a passthrough to the standard library so that packages importing this package can still unwrap errors.
+func Is(err, target error) bool {
+	return goerrors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target, and if one is found, sets target to that error value and returns true.
+// It is a passthrough to the standard library so that packages importing this package can still unwrap errors.
+func As(err error, target interface{}) bool {
+	return goerrors.As(err, target)
+}
@@ -0,0 +1,64 @@
+// Package page lists a page of names the way object stores do, for providers that keep their files in a flat namespace.
+package page
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+/*
+Names returns the page of names described by opts.
+
+Names beginning with opts.Prefix are returned in lexical order. When opts.Delimiter is set, the names that contain it
+after the prefix are grouped into prefixes, which count towards opts.PageSize like names do. next is the token of the
+following page, and is empty on the last page. names does not need to be sorted and is not modified.
+*/
+func Names(names []string, opts types.ListOptions) (files []string, prefixes []string, next string) {
+	sorted := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, opts.Prefix) {
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Strings(sorted)
+
+	size := opts.PageSize
+	if size <= 0 {
+		size = config.DefaultPageSize
+	}
+	token := opts.ContinuationToken
+
+	var last string
+	count := 0
+	for _, name := range sorted {
+		if token != "" && (name <= token || (opts.Delimiter != "" && strings.HasSuffix(token, opts.Delimiter) && strings.HasPrefix(name, token))) {
+			continue
+		}
+
+		entry, isPrefix := name, false
+		if opts.Delimiter != "" {
+			if i := strings.Index(name[len(opts.Prefix):], opts.Delimiter); i >= 0 {
+				entry, isPrefix = name[:len(opts.Prefix)+i+len(opts.Delimiter)], true
+			}
+		}
+		if isPrefix && entry == last {
+			// already grouped
+			continue
+		}
+
+		if count == size {
+			return files, prefixes, last
+		}
+		if isPrefix {
+			prefixes = append(prefixes, entry)
+		} else {
+			files = append(files, entry)
+		}
+		last = entry
+		count++
+	}
+	return files, prefixes, ""
+}
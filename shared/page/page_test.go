@@ -0,0 +1,50 @@
+package page_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opensaucerer/bifrost/shared/page"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+func TestNames(t *testing.T) {
+	names := []string{"b.txt", "a/2.txt", "a/1.txt", "c/d/e.txt", "a.txt", "c/f.txt"}
+
+	t.Run("Tests Names lists every name in order", func(t *testing.T) {
+		files, prefixes, next := page.Names(names, types.ListOptions{})
+		want := []string{"a.txt", "a/1.txt", "a/2.txt", "b.txt", "c/d/e.txt", "c/f.txt"}
+		if !reflect.DeepEqual(files, want) || prefixes != nil || next != "" {
+			t.Errorf("Unexpected page %v %v %q", files, prefixes, next)
+		}
+	})
+
+	t.Run("Tests Names groups names by delimiter", func(t *testing.T) {
+		files, prefixes, _ := page.Names(names, types.ListOptions{Delimiter: "/"})
+		if !reflect.DeepEqual(files, []string{"a.txt", "b.txt"}) || !reflect.DeepEqual(prefixes, []string{"a/", "c/"}) {
+			t.Errorf("Unexpected page %v %v", files, prefixes)
+		}
+
+		files, prefixes, _ = page.Names(names, types.ListOptions{Prefix: "c/", Delimiter: "/"})
+		if !reflect.DeepEqual(files, []string{"c/f.txt"}) || !reflect.DeepEqual(prefixes, []string{"c/d/"}) {
+			t.Errorf("Unexpected page %v %v", files, prefixes)
+		}
+	})
+
+	t.Run("Tests Names pages through every entry", func(t *testing.T) {
+		var all []string
+		opts := types.ListOptions{Delimiter: "/", PageSize: 1}
+		for i := 0; i < 10; i++ {
+			files, prefixes, next := page.Names(names, opts)
+			all = append(all, files...)
+			all = append(all, prefixes...)
+			if next == "" {
+				break
+			}
+			opts.ContinuationToken = next
+		}
+		if !reflect.DeepEqual(all, []string{"a.txt", "a/", "b.txt", "c/"}) {
+			t.Errorf("Unexpected entries %v", all)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+// Package pool runs batches of work on a bounded number of goroutines.
+package pool
+
+import (
+	"sync"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+)
+
+// Size returns the number of workers to use for a batch.
+// It is 1 when useAsync is false, and max, or config.DefaultMaxConcurrency when max is not positive, otherwise.
+func Size(useAsync bool, max int) int {
+	if !useAsync {
+		return 1
+	}
+	if max <= 0 {
+		return config.DefaultMaxConcurrency
+	}
+	return max
+}
+
+/*
+Run calls fn for every index in [0, n) on at most size goroutines and returns once all calls have returned.
+
+fn is called sequentially on the calling goroutine when size is 1 or less.
+Callers that collect results should write them by index so that their order does not depend on scheduling.
+*/
+func Run(n, size int, fn func(i int)) {
+	if size <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	if size > n {
+		size = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(size)
+	for w := 0; w < size; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
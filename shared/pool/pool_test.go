@@ -0,0 +1,55 @@
+package pool_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/pool"
+)
+
+func TestPool(t *testing.T) {
+
+	t.Run("Tests Run keeps results aligned with their index", func(t *testing.T) {
+		results := make([]int, 50)
+		pool.Run(len(results), 8, func(i int) {
+			results[i] = i * i
+		})
+		for i, r := range results {
+			if r != i*i {
+				t.Errorf("Expected result %d at index %d, got %d", i*i, i, r)
+			}
+		}
+	})
+
+	t.Run("Tests Run never exceeds the pool size", func(t *testing.T) {
+		var running, peak int32
+		var mu sync.Mutex
+		pool.Run(20, 3, func(i int) {
+			n := atomic.AddInt32(&running, 1)
+			mu.Lock()
+			if n > peak {
+				peak = n
+			}
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		})
+		if peak > 3 {
+			t.Errorf("Expected at most 3 concurrent calls, got %d", peak)
+		}
+	})
+
+	t.Run("Tests Size falls back to the default when async", func(t *testing.T) {
+		if s := pool.Size(false, 8); s != 1 {
+			t.Errorf("Expected size 1 without async, got %d", s)
+		}
+		if s := pool.Size(true, 0); s <= 1 {
+			t.Errorf("Expected default size above 1 with async, got %d", s)
+		}
+		if s := pool.Size(true, 4); s != 4 {
+			t.Errorf("Expected size 4, got %d", s)
+		}
+	})
+}
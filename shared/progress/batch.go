@@ -0,0 +1,91 @@
+package progress
+
+import (
+	"os"
+	"sync"
+
+	"github.com/opensaucerer/bifrost/shared/stream"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// Batch aggregates the progress of the files of a MultiFile into BatchProgress events.
+// A nil *Batch is valid and reports nothing.
+type Batch struct {
+	mu    sync.Mutex
+	fn    types.BatchProgressFunc
+	files []types.Progress
+	state types.BatchProgress
+}
+
+// NewBatch returns a Batch reporting to multiFile.Progress, or nil if multiFile.Progress is nil.
+// The size of each file is looked up once so that the batch total is known before any upload starts.
+func NewBatch(multiFile types.MultiFile) *Batch {
+	if multiFile.Progress == nil {
+		return nil
+	}
+	b := &Batch{
+		fn:    multiFile.Progress,
+		files: make([]types.Progress, len(multiFile.Files)),
+		state: types.BatchProgress{Files: len(multiFile.Files)},
+	}
+	for i, file := range multiFile.Files {
+		total := int64(-1)
+		if file.Path != "" {
+			if fi, err := os.Stat(file.Path); err == nil {
+				total = fi.Size()
+			}
+		} else if file.Handle != nil {
+			total = stream.Size(file.Handle)
+		}
+		b.files[i] = types.Progress{Name: file.Filename, Path: file.Path, Total: total}
+		if b.state.Total >= 0 {
+			if total < 0 {
+				b.state.Total = -1
+			} else {
+				b.state.Total += total
+			}
+		}
+	}
+	return b
+}
+
+// Track returns file with its Progress callback wrapped to also report to the batch as the i-th file.
+func (b *Batch) Track(i int, file types.File) types.File {
+	if b == nil {
+		return file
+	}
+	fn := file.Progress
+	file.Progress = func(p types.Progress) {
+		if fn != nil {
+			fn(p)
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.state.Bytes += p.Bytes - b.files[i].Bytes
+		b.files[i] = p
+		b.report(i)
+	}
+	return file
+}
+
+// Done records that the i-th file finished uploading, failing with err if it is not nil.
+func (b *Batch) Done(i int, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state.Completed++
+	if err != nil {
+		b.state.Failed++
+	}
+	b.report(i)
+}
+
+// report calls the batch callback with the current state. It must be called with b.mu held.
+func (b *Batch) report(i int) {
+	event := b.state
+	event.File = b.files[i]
+	event.Index = i
+	b.fn(event)
+}
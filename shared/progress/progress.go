@@ -0,0 +1,73 @@
+// Package progress reports the progress of uploads to the callbacks set on bifrost files.
+package progress
+
+import (
+	"io"
+
+	"github.com/opensaucerer/bifrost/shared/stream"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// reader calls fn with the number of bytes read from r after every read.
+type reader struct {
+	r        io.Reader
+	fn       types.ProgressFunc
+	progress types.Progress
+}
+
+// readSeeker is a reader over a seekable stream which keeps the reported bytes in step with the stream offset.
+type readSeeker struct {
+	*reader
+	seeker io.Seeker
+	start  int64
+}
+
+/*
+Reader returns r wrapped so that file.Progress is called after every read from it, or r itself if file.Progress is nil.
+
+The total size is taken from r when it can be known without reading it. The returned reader is an io.Seeker if r is one,
+so that providers can still size and rewind it.
+*/
+func Reader(r io.Reader, file types.File) io.Reader {
+	if file.Progress == nil {
+		return r
+	}
+	pr := &reader{
+		r:  r,
+		fn: file.Progress,
+		progress: types.Progress{
+			Name:  file.Filename,
+			Path:  file.Path,
+			Total: stream.Size(r),
+		},
+	}
+	if s, ok := r.(io.Seeker); ok {
+		if start, err := s.Seek(0, io.SeekCurrent); err == nil {
+			return &readSeeker{reader: pr, seeker: s, start: start}
+		}
+	}
+	return pr
+}
+
+// Read reads from the underlying reader and reports the bytes read so far.
+func (pr *reader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		pr.progress.Bytes += int64(n)
+		pr.fn(pr.progress)
+	}
+	return n, err
+}
+
+// Seek seeks the underlying reader and moves the reported bytes to the new offset.
+func (rs *readSeeker) Seek(offset int64, whence int) (int64, error) {
+	off, err := rs.seeker.Seek(offset, whence)
+	if err != nil {
+		return off, err
+	}
+	rs.progress.Bytes = off - rs.start
+	if rs.progress.Bytes < 0 {
+		rs.progress.Bytes = 0
+	}
+	return off, nil
+}
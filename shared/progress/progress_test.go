@@ -0,0 +1,108 @@
+package progress_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/opensaucerer/bifrost/shared/progress"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+func TestProgress(t *testing.T) {
+
+	t.Run("Tests Reader reports bytes read and the total size", func(t *testing.T) {
+		var events []types.Progress
+		r := progress.Reader(strings.NewReader("hello world"), types.File{
+			Filename: "hello.txt",
+			Progress: func(p types.Progress) { events = append(events, p) },
+		})
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			t.Fatal(err)
+		}
+		if len(events) == 0 {
+			t.Fatal("Expected at least one progress event")
+		}
+		last := events[len(events)-1]
+		if last.Name != "hello.txt" || last.Bytes != 11 || last.Total != 11 {
+			t.Errorf("Unexpected final progress %+v", last)
+		}
+	})
+
+	t.Run("Tests Reader keeps seekable readers seekable", func(t *testing.T) {
+		var last types.Progress
+		r := progress.Reader(strings.NewReader("hello world"), types.File{
+			Progress: func(p types.Progress) { last = p },
+		})
+		s, ok := r.(io.ReadSeeker)
+		if !ok {
+			t.Fatal("Expected an io.ReadSeeker")
+		}
+		io.Copy(io.Discard, s)
+		if _, err := s.Seek(0, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 5)
+		s.Read(buf)
+		if last.Bytes != 5 {
+			t.Errorf("Expected progress to restart after a rewind, got %d bytes", last.Bytes)
+		}
+	})
+
+	t.Run("Tests Reader reports an unknown total for streams", func(t *testing.T) {
+		var last types.Progress
+		r := progress.Reader(io.MultiReader(bytes.NewReader([]byte("hello"))), types.File{
+			Progress: func(p types.Progress) { last = p },
+		})
+		io.Copy(io.Discard, r)
+		if last.Bytes != 5 || last.Total != -1 {
+			t.Errorf("Unexpected final progress %+v", last)
+		}
+	})
+
+	t.Run("Tests Reader returns the reader as is without a callback", func(t *testing.T) {
+		sr := strings.NewReader("hello")
+		if r := progress.Reader(sr, types.File{}); r != sr {
+			t.Error("Expected the reader to be returned unwrapped")
+		}
+	})
+
+	t.Run("Tests Batch aggregates the progress of every file", func(t *testing.T) {
+		var events []types.BatchProgress
+		multiFile := types.MultiFile{
+			Files: []types.File{
+				{Handle: strings.NewReader("hello"), Filename: "a"},
+				{Handle: strings.NewReader("world!"), Filename: "b"},
+			},
+			Progress: func(p types.BatchProgress) { events = append(events, p) },
+		}
+		batch := progress.NewBatch(multiFile)
+		for i := range multiFile.Files {
+			file := batch.Track(i, multiFile.Files[i])
+			io.Copy(io.Discard, progress.Reader(file.Handle, file))
+			var err error
+			if i == 1 {
+				err = errors.New("failed")
+			}
+			batch.Done(i, err)
+		}
+		last := events[len(events)-1]
+		if last.Bytes != 11 || last.Total != 11 || last.Completed != 2 || last.Failed != 1 || last.Files != 2 || last.Index != 1 {
+			t.Errorf("Unexpected final batch progress %+v", last)
+		}
+	})
+
+	t.Run("Tests a nil Batch is a no-op", func(t *testing.T) {
+		batch := progress.NewBatch(types.MultiFile{Files: []types.File{{Path: "a"}}})
+		if batch != nil {
+			t.Fatal("Expected a nil batch without a callback")
+		}
+		file := batch.Track(0, types.File{Path: "a"})
+		batch.Done(0, nil)
+		if file.Progress != nil {
+			t.Error("Expected the file to be returned as is")
+		}
+	})
+}
@@ -8,8 +8,29 @@ import (
 	"github.com/opensaucerer/bifrost/shared/config"
 )
 
-// NewClient returns a new client for making requests
+// NewClient returns a new client for making requests authenticated with a bearer token
 func NewClient(url string, token string, timeout int64) *Client {
+	c := newClient(url, timeout)
+	if c == nil {
+		return nil
+	}
+	c.Request.Header.Add(config.ReqAuth, fmt.Sprintf(config.ReqBearer, token))
+	return c
+}
+
+// NewKeyClient returns a new client for making requests authenticated with a Pinata API key and secret
+func NewKeyClient(url string, key, secret string, timeout int64) *Client {
+	c := newClient(url, timeout)
+	if c == nil {
+		return nil
+	}
+	c.Request.Header.Add(config.ReqPinataAPIKey, key)
+	c.Request.Header.Add(config.ReqPinataSecretAPIKey, secret)
+	return c
+}
+
+// newClient returns a new client without any authentication
+func newClient(url string, timeout int64) *Client {
 	h := &http.Client{}
 	if timeout > 0 {
 		h.Timeout = time.Duration(timeout) * time.Second
@@ -19,7 +40,6 @@ func NewClient(url string, token string, timeout int64) *Client {
 	if err != nil {
 		return nil
 	}
-	req.Header.Add(config.ReqAuth, fmt.Sprintf(config.ReqBearer, token))
 	return &Client{
 		Http:    h,
 		Request: req,
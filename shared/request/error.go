@@ -0,0 +1,11 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error returns the error message.
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("request failed with status %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), string(e.Body))
+}
@@ -2,55 +2,195 @@ package request
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"os"
 
 	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/stream"
 	"github.com/opensaucerer/bifrost/shared/types"
 )
 
-func (c *Client) PostForm(url string, params types.Param) ([]byte, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	for _, pf := range params.Files {
+// PostForm sends a multipart POST request with the given files and data to url and returns the response body.
+// The request is cancelled when ctx is done.
+//
+// The multipart body is streamed to the server as it is written so that files are never held in memory. The
+// Content-Length header is set when the size of every file is known, otherwise the body is sent chunked.
+// A *ResponseError is returned along with the body if the response status code is not 2xx.
+func (c *Client) PostForm(ctx context.Context, url string, params types.Param) ([]byte, error) {
+	// open files up front so that a missing file fails the call before anything is sent
+	handles := make([]io.Reader, len(params.Files))
+	sizes := make([]int64, len(params.Files))
+	var files []*os.File
+	closeFiles := func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}
+	for i, pf := range params.Files {
 		if pf.Path != "" {
 			// open file
 			file, err := os.Open(pf.Path)
 			if err != nil {
+				closeFiles()
 				return nil, err
 			}
+			files = append(files, file)
 			pf.Handle = file
-			// close file
-			defer file.Close()
 		}
+		if pf.Handle == nil {
+			closeFiles()
+			return nil, fmt.Errorf("no handle or path given for file %s", pf.Name)
+		}
+		handles[i] = pf.Handle
+		sizes[i] = stream.Size(pf.Handle)
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	// copy request
+	req := c.Request.Clone(ctx)
+	req.Method = config.MethodPost
+	u, err := c.Request.URL.Parse(url)
+	if err != nil {
+		closeFiles()
+		return nil, err
+	}
+	req.URL = u
+	req.Header.Set(config.ReqContentType, writer.FormDataContentType())
+	req.Body = pr
+	req.ContentLength = formLength(writer.Boundary(), params, sizes)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer closeFiles()
+		pw.CloseWithError(writeForm(writer, params, handles))
+	}()
+
+	// make request
+	resp, err := c.Http.Do(req)
+	if err != nil {
+		// unblock the writer if the transport did not consume the body
+		pr.CloseWithError(err)
+		<-done
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// the server can answer before reading the whole body (e.g. with a 503), so stop the writer and wait for it to
+	// exit: the caller closes or rewinds the handles once PostForm returns
+	defer func() {
+		pr.CloseWithError(errResponseReceived)
+		<-done
+	}()
+
+	// read response
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return b, &ResponseError{StatusCode: resp.StatusCode, Body: b}
+	}
+	return b, nil
+}
+
+// errResponseReceived stops the writer of a multipart body once the response has been received.
+var errResponseReceived = errors.New("response received before the request body was sent")
 
+// writeForm writes the multipart form for params to writer, reading file contents from handles.
+func writeForm(writer *multipart.Writer, params types.Param, handles []io.Reader) error {
+	for i, pf := range params.Files {
 		part, err := writer.CreateFormFile(pf.Key, pf.Name)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		_, err = io.Copy(part, pf.Handle)
-		if err != nil {
-			return nil, err
+		if handles[i] == nil {
+			continue
+		}
+		if _, err := io.Copy(part, handles[i]); err != nil {
+			return err
 		}
 	}
 
 	for _, pd := range params.Data {
-		_ = writer.WriteField(pd.Key, pd.Value)
+		if err := writer.WriteField(pd.Key, pd.Value); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+// formLength returns the length of the multipart form for params written with boundary, or -1 if the size of any
+// file is unknown.
+func formLength(boundary string, params types.Param, sizes []int64) int64 {
+	var n int64
+	for _, size := range sizes {
+		if size < 0 {
+			return -1
+		}
+		n += size
 	}
 
-	err := writer.Close()
+	// write the form without file contents to count the bytes added by the multipart encoding
+	cw := &countWriter{}
+	writer := multipart.NewWriter(cw)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return -1
+	}
+	if err := writeForm(writer, params, make([]io.Reader, len(params.Files))); err != nil {
+		return -1
+	}
+	return n + cw.n
+}
+
+// PostJSON sends body, marshalled as JSON, in a POST request to url and returns the response body. The request is
+// cancelled when ctx is done. A *ResponseError is returned along with the body if the response status code is not 2xx.
+func (c *Client) PostJSON(ctx context.Context, url string, body interface{}) ([]byte, error) {
+	b, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
+	return c.do(ctx, config.MethodPost, url, b)
+}
+
+// Get sends a GET request to the given url and returns the response body. The request is cancelled when ctx is done.
+// A *ResponseError is returned along with the body if the response status code is not 2xx.
+func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
+	return c.do(ctx, config.MethodGet, url, nil)
+}
 
+// Delete sends a DELETE request to the given url and returns the response body. The request is cancelled when ctx is done.
+// A *ResponseError is returned along with the body if the response status code is not 2xx.
+func (c *Client) Delete(ctx context.Context, url string) ([]byte, error) {
+	return c.do(ctx, config.MethodDelete, url, nil)
+}
+
+// do sends a request, with body as a JSON document unless it is nil, and returns the response body.
+func (c *Client) do(ctx context.Context, method, url string, body []byte) ([]byte, error) {
 	// copy request
-	req := c.Request.Clone(c.Request.Context())
-	req.Method = config.MethodPost
-	req.URL, _ = c.Request.URL.Parse(url)
-	req.Header.Add(config.ReqContentType, writer.FormDataContentType())
-	req.Body = io.NopCloser(body)
+	req := c.Request.Clone(ctx)
+	req.Method = method
+	u, err := c.Request.URL.Parse(url)
+	if err != nil {
+		return nil, err
+	}
+	req.URL = u
+	if body != nil {
+		req.Header.Set(config.ReqContentType, config.ReqJSON)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
 
 	// make request
 	resp, err := c.Http.Do(req)
@@ -60,5 +200,18 @@ func (c *Client) PostForm(url string, params types.Param) ([]byte, error) {
 	defer resp.Body.Close()
 
 	// read response
-	return io.ReadAll(resp.Body)
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return b, &ResponseError{StatusCode: resp.StatusCode, Body: b}
+	}
+	return b, nil
+}
+
+// Write counts len(p) and discards p.
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
 }
@@ -0,0 +1,190 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/request"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+func TestPostForm(t *testing.T) {
+	var (
+		length   int64
+		chunked  bool
+		file     string
+		metadata string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		length = r.ContentLength
+		chunked = len(r.TransferEncoding) > 0 && r.TransferEncoding[0] == "chunked"
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		b, _ := io.ReadAll(f)
+		file = string(b)
+		metadata = r.FormValue("pinataMetadata")
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"reason":"INVALID_CREDENTIALS"}}`))
+			return
+		}
+		w.Write([]byte(`{"IpfsHash":"cid"}`))
+	}))
+	defer server.Close()
+
+	params := func(r io.Reader) types.Param {
+		return types.Param{
+			Files: []types.ParamFile{{Key: "file", Name: "hello.txt", Handle: r}},
+			Data:  []types.ParamData{{Key: "pinataMetadata", Value: `{"name":"hello.txt"}`}},
+		}
+	}
+
+	t.Run("Tests PostForm sets Content-Length for readers of known size", func(t *testing.T) {
+		c := request.NewClient(server.URL, "token", 10)
+		b, err := c.PostForm(context.Background(), server.URL, params(strings.NewReader("hello world")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `{"IpfsHash":"cid"}` {
+			t.Errorf("Unexpected response %s", b)
+		}
+		if length <= int64(len("hello world")) || chunked {
+			t.Errorf("Expected a Content-Length, got %d (chunked %v)", length, chunked)
+		}
+		if file != "hello world" || metadata != `{"name":"hello.txt"}` {
+			t.Errorf("Unexpected form file %q and metadata %q", file, metadata)
+		}
+	})
+
+	t.Run("Tests PostForm streams readers of unknown size", func(t *testing.T) {
+		c := request.NewClient(server.URL, "token", 10)
+		if _, err := c.PostForm(context.Background(), server.URL, params(io.MultiReader(strings.NewReader("hello world")))); err != nil {
+			t.Fatal(err)
+		}
+		if length != -1 || !chunked {
+			t.Errorf("Expected a chunked body, got Content-Length %d", length)
+		}
+		if file != "hello world" {
+			t.Errorf("Unexpected form file %q", file)
+		}
+	})
+
+	t.Run("Tests PostForm returns a ResponseError for non-2xx responses", func(t *testing.T) {
+		c := request.NewClient(server.URL, "wrong", 10)
+		b, err := c.PostForm(context.Background(), server.URL, params(strings.NewReader("hello world")))
+		var re *request.ResponseError
+		if !errors.As(err, &re) {
+			t.Fatalf("Expected a *request.ResponseError, got %v", err)
+		}
+		if re.StatusCode != http.StatusUnauthorized || !strings.Contains(string(b), "INVALID_CREDENTIALS") {
+			t.Errorf("Unexpected error %v with body %s", re, b)
+		}
+	})
+
+	t.Run("Tests PostForm stops reading the file when the server answers early", func(t *testing.T) {
+		early := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer early.Close()
+
+		handle := &endlessReader{}
+		c := request.NewClient(early.URL, "token", 10)
+		_, err := c.PostForm(context.Background(), early.URL, params(handle))
+		var re *request.ResponseError
+		if !errors.As(err, &re) || re.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Expected a 503 ResponseError, got %v", err)
+		}
+
+		// the handle is closed or rewound by the caller once PostForm returns, so it must not be read anymore
+		reads := atomic.LoadInt64(&handle.reads)
+		time.Sleep(100 * time.Millisecond)
+		if n := atomic.LoadInt64(&handle.reads); n != reads {
+			t.Errorf("Expected the handle not to be read after PostForm returned, got %d more reads", n-reads)
+		}
+	})
+
+	t.Run("Tests PostForm fails for missing files", func(t *testing.T) {
+		c := request.NewClient(server.URL, "token", 10)
+		_, err := c.PostForm(context.Background(), server.URL, types.Param{
+			Files: []types.ParamFile{{Key: "file", Name: "missing", Path: "does/not/exist"}},
+		})
+		if err == nil {
+			t.Error("Expected an error for a missing file")
+		}
+	})
+}
+
+func TestPostJSON(t *testing.T) {
+	var (
+		contentType string
+		body        string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"IpfsHash":"cid"}`))
+	}))
+	defer server.Close()
+
+	t.Run("Tests PostJSON sends a JSON document", func(t *testing.T) {
+		c := request.NewClient(server.URL, "token", 10)
+		b, err := c.PostJSON(context.Background(), server.URL, map[string]string{"name": "bifrost"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `{"IpfsHash":"cid"}` {
+			t.Errorf("Unexpected response %s", b)
+		}
+		if contentType != "application/json" || body != `{"name":"bifrost"}` {
+			t.Errorf("Unexpected content type %q and body %q", contentType, body)
+		}
+	})
+}
+
+func TestNewKeyClient(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte(`{"message":"Congratulations! You are communicating with the Pinata API!"}`))
+	}))
+	defer server.Close()
+
+	t.Run("Tests NewKeyClient sends the API key and secret", func(t *testing.T) {
+		c := request.NewKeyClient(server.URL, "key", "secret", 10)
+		if _, err := c.Get(context.Background(), server.URL); err != nil {
+			t.Fatal(err)
+		}
+		if header.Get("pinata_api_key") != "key" || header.Get("pinata_secret_api_key") != "secret" {
+			t.Errorf("Unexpected headers %v", header)
+		}
+		if auth := header.Get("Authorization"); auth != "" {
+			t.Errorf("Expected no Authorization header, got %q", auth)
+		}
+	})
+}
+
+// endlessReader is a slow reader of unknown size that never ends, counting the reads that have returned.
+type endlessReader struct {
+	reads int64
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	time.Sleep(10 * time.Millisecond)
+	atomic.AddInt64(&r.reads, 1)
+	return len(p), nil
+}
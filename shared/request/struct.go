@@ -8,3 +8,16 @@ type Client struct {
 	Http    *http.Client
 	Request *http.Request
 }
+
+// ResponseError is returned when a request completes with a non-2xx status code.
+type ResponseError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Body is the raw response body.
+	Body []byte
+}
+
+// countWriter counts the bytes written to it and discards them.
+type countWriter struct {
+	n int64
+}
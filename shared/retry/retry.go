@@ -0,0 +1,130 @@
+// Package retry retries provider operations that fail with transient errors.
+package retry
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+	"github.com/opensaucerer/bifrost/shared/errors"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+/*
+Do calls fn until it succeeds, fails with an error that is not retryable, the attempts allowed by policy are used up or
+ctx is done, and returns the last error from fn.
+
+A nil policy calls fn once. policy.Retryable, when set, takes precedence over retryable.
+*/
+func Do(ctx context.Context, policy *types.RetryPolicy, retryable func(error) bool, fn func(attempt int) error) error {
+	if policy != nil && policy.Retryable != nil {
+		retryable = policy.Retryable
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts || retryable == nil || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(Delay(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+/*
+Upload is like Do for an upload of file. A Handle is rewound to its starting offset before every retry, and an upload
+from a Handle that cannot be rewound (i.e. is not an io.Seeker) is never retried since part of it might already have
+been consumed. Files uploaded from a Path are reopened by fn on every attempt.
+*/
+func Upload(ctx context.Context, policy *types.RetryPolicy, retryable func(error) bool, file types.File, fn func() error) error {
+	rewind := func() error { return nil }
+	if file.Path == "" && file.Handle != nil {
+		rewind = nil
+		if s, ok := file.Handle.(io.Seeker); ok {
+			if start, err := s.Seek(0, io.SeekCurrent); err == nil {
+				rewind = func() error {
+					_, err := s.Seek(start, io.SeekStart)
+					return err
+				}
+			}
+		}
+	}
+	if rewind == nil {
+		// refuse to retry streams which cannot be sent again
+		return fn()
+	}
+
+	var rewindErr error
+	err := Do(ctx, policy, retryable, func(attempt int) error {
+		if attempt > 1 {
+			if rewindErr = rewind(); rewindErr != nil {
+				return rewindErr
+			}
+		}
+		return fn()
+	})
+	if rewindErr != nil {
+		return &errors.BifrostError{
+			Err:       rewindErr,
+			ErrorCode: errors.ErrFileOperationFailed,
+		}
+	}
+	return err
+}
+
+// Delay returns the delay before the retry that follows the given attempt.
+func Delay(policy *types.RetryPolicy, attempt int) time.Duration {
+	base, max := policy.BaseDelay, policy.MaxDelay
+	if base <= 0 {
+		base = config.DefaultRetryBaseDelay
+	}
+	if max <= 0 {
+		max = config.DefaultRetryMaxDelay
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	if jitter := policy.Jitter; jitter > 0 {
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(float64(delay) * jitter * rand.Float64())
+	}
+	return delay
+}
+
+// IsTransient returns true if err is a network failure that is likely to succeed when tried again, such as a reset
+// connection or a request timeout. Cancelled and expired contexts are never transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// IsRetryableStatus returns true if an HTTP response with the status code is worth retrying, i.e. it is a request
+// timeout, a rate limit or a server error.
+func IsRetryableStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
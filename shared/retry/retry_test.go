@@ -0,0 +1,150 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/opensaucerer/bifrost/shared/retry"
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+var errTransient = errors.New("transient")
+
+func isTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+func TestRetry(t *testing.T) {
+	policy := &types.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	t.Run("Tests Do retries transient errors up to MaxAttempts", func(t *testing.T) {
+		calls := 0
+		err := retry.Do(context.Background(), policy, isTransient, func(attempt int) error {
+			calls++
+			return errTransient
+		})
+		if !errors.Is(err, errTransient) || calls != 3 {
+			t.Errorf("Expected 3 calls ending in the transient error, got %d calls and %v", calls, err)
+		}
+	})
+
+	t.Run("Tests Do stops at the first success or permanent error", func(t *testing.T) {
+		calls := 0
+		err := retry.Do(context.Background(), policy, isTransient, func(attempt int) error {
+			calls++
+			if attempt == 1 {
+				return errTransient
+			}
+			return nil
+		})
+		if err != nil || calls != 2 {
+			t.Errorf("Expected success on the second call, got %d calls and %v", calls, err)
+		}
+
+		calls = 0
+		permanent := errors.New("permanent")
+		err = retry.Do(context.Background(), policy, isTransient, func(attempt int) error {
+			calls++
+			return permanent
+		})
+		if err != permanent || calls != 1 {
+			t.Errorf("Expected a single call for a permanent error, got %d calls and %v", calls, err)
+		}
+	})
+
+	t.Run("Tests Do calls fn once without a policy", func(t *testing.T) {
+		calls := 0
+		retry.Do(context.Background(), nil, isTransient, func(attempt int) error {
+			calls++
+			return errTransient
+		})
+		if calls != 1 {
+			t.Errorf("Expected a single call, got %d", calls)
+		}
+	})
+
+	t.Run("Tests Do prefers the policy classifier", func(t *testing.T) {
+		calls := 0
+		retry.Do(context.Background(), &types.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			Retryable:   func(error) bool { return false },
+		}, isTransient, func(attempt int) error {
+			calls++
+			return errTransient
+		})
+		if calls != 1 {
+			t.Errorf("Expected a single call, got %d", calls)
+		}
+	})
+
+	t.Run("Tests Do gives up when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		retry.Do(ctx, &types.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}, isTransient, func(attempt int) error {
+			calls++
+			cancel()
+			return errTransient
+		})
+		if calls != 1 {
+			t.Errorf("Expected a single call, got %d", calls)
+		}
+	})
+
+	t.Run("Tests Upload rewinds seekable handles between attempts", func(t *testing.T) {
+		handle := strings.NewReader("hello world")
+		var reads []string
+		err := retry.Upload(context.Background(), policy, isTransient, types.File{Handle: handle}, func() error {
+			b, _ := io.ReadAll(handle)
+			reads = append(reads, string(b))
+			if len(reads) < 3 {
+				return errTransient
+			}
+			return nil
+		})
+		if err != nil || len(reads) != 3 || reads[2] != "hello world" {
+			t.Errorf("Expected the full handle on every attempt, got %q and %v", reads, err)
+		}
+	})
+
+	t.Run("Tests Upload refuses to retry non-seekable handles", func(t *testing.T) {
+		calls := 0
+		retry.Upload(context.Background(), policy, isTransient, types.File{Handle: io.MultiReader(strings.NewReader("hello"))}, func() error {
+			calls++
+			return errTransient
+		})
+		if calls != 1 {
+			t.Errorf("Expected a single call, got %d", calls)
+		}
+	})
+
+	t.Run("Tests Delay grows exponentially up to MaxDelay", func(t *testing.T) {
+		p := &types.RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+		for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+			if got := retry.Delay(p, attempt+1); got != want*time.Millisecond {
+				t.Errorf("Expected a delay of %dms after attempt %d, got %s", want, attempt+1, got)
+			}
+		}
+
+		p.Jitter = 0.5
+		for i := 0; i < 100; i++ {
+			if got := retry.Delay(p, 2); got < 100*time.Millisecond || got > 200*time.Millisecond {
+				t.Fatalf("Expected a jittered delay between 100ms and 200ms, got %s", got)
+			}
+		}
+	})
+
+	t.Run("Tests IsTransient", func(t *testing.T) {
+		if !retry.IsTransient(syscall.ECONNRESET) || !retry.IsTransient(io.ErrUnexpectedEOF) {
+			t.Error("Expected connection resets and unexpected EOFs to be transient")
+		}
+		if retry.IsTransient(context.Canceled) || retry.IsTransient(errors.New("bad request")) {
+			t.Error("Expected cancellations and other errors not to be transient")
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package stream
+
+import (
+	"io"
+	"os"
+)
+
+/*
+Section returns the bytes left to read from r as an io.SectionReader, which can be read concurrently from independent
+offsets. Readers that can already be read at random offsets, such as files and bytes.Reader, are used in place while
+other readers are first copied to a temporary file.
+
+cleanup removes the temporary file, if any, and must be called once the section is no longer read.
+*/
+func Section(r io.Reader) (section *io.SectionReader, cleanup func(), err error) {
+	if ra, ok := r.(io.ReaderAt); ok {
+		if s, ok := r.(io.Seeker); ok {
+			if size := Size(r); size >= 0 {
+				if off, err := s.Seek(0, io.SeekCurrent); err == nil {
+					return io.NewSectionReader(ra, off, size), func() {}, nil
+				}
+			}
+		}
+	}
+
+	tmp, err := os.CreateTemp("", "bifrost-*.tmp")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup = func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return io.NewSectionReader(tmp, 0, size), cleanup, nil
+}
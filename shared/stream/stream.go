@@ -0,0 +1,78 @@
+// Package stream holds io helpers shared by the providers.
+package stream
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// cancelCloser is an io.ReadCloser that cancels a context once closed.
+type cancelCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the underlying reader and cancels its context.
+func (c *cancelCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// CancelOnClose returns rc with cancel called once it is closed.
+// It keeps the context of a streaming read alive until the caller is done reading.
+func CancelOnClose(rc io.ReadCloser, cancel context.CancelFunc) io.ReadCloser {
+	return &cancelCloser{ReadCloser: rc, cancel: cancel}
+}
+
+// SaveFile writes r to the file at name and returns the number of bytes written.
+// The data is written to a temporary file in the same directory which is then renamed to name,
+// so name is either left untouched or completely written.
+func SaveFile(name string, r io.Reader) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	// remove the temporary file if anything goes wrong, this is a no-op once it has been renamed
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err == nil {
+		// temporary files are created private, give the file the usual permissions
+		err = tmp.Chmod(0644)
+	}
+	if err != nil {
+		tmp.Close()
+		return n, err
+	}
+	if err := tmp.Close(); err != nil {
+		return n, err
+	}
+	return n, os.Rename(tmp.Name(), name)
+}
+
+// Size returns the number of bytes left to read from r, or -1 when it cannot be known without reading r.
+// Seekable readers are left at their current offset.
+func Size(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		// bytes.Reader, bytes.Buffer and strings.Reader
+		return int64(v.Len())
+	case io.Seeker:
+		cur, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := v.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - cur
+	}
+	return -1
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/opensaucerer/bifrost/shared/errors"
+)
+
+// AsFile asserts that fileFace is a File or a non-nil *File and returns the file.
+func AsFile(fileFace interface{}) (File, error) {
+	switch f := fileFace.(type) {
+	case File:
+		return f, nil
+	case *File:
+		if f != nil {
+			return *f, nil
+		}
+	}
+	return File{}, &errors.BifrostError{
+		Err:       fmt.Errorf("argument must be of type bifrost.File"),
+		ErrorCode: errors.ErrBadRequest,
+	}
+}
+
+// AsMultiFile asserts that multiFace is a MultiFile or a non-nil *MultiFile and returns the multi file.
+func AsMultiFile(multiFace interface{}) (MultiFile, error) {
+	switch m := multiFace.(type) {
+	case MultiFile:
+		return m, nil
+	case *MultiFile:
+		if m != nil {
+			return *m, nil
+		}
+	}
+	return MultiFile{}, &errors.BifrostError{
+		Err:       fmt.Errorf("argument must be of type bifrost.MultiFile"),
+		ErrorCode: errors.ErrBadRequest,
+	}
+}
+
+// AsFolder asserts that foldFace is a Folder or a non-nil *Folder and returns the folder.
+func AsFolder(foldFace interface{}) (Folder, error) {
+	switch f := foldFace.(type) {
+	case Folder:
+		return f, nil
+	case *Folder:
+		if f != nil {
+			return *f, nil
+		}
+	}
+	return Folder{}, &errors.BifrostError{
+		Err:       fmt.Errorf("argument must be of type bifrost.Folder"),
+		ErrorCode: errors.ErrBadRequest,
+	}
+}
+
+// AsDeleteFile asserts that fileFace is a DeleteFile or a non-nil *DeleteFile and returns the file.
+// A File is also accepted for backwards compatibility, in which case only its Filename and Options are used.
+func AsDeleteFile(fileFace interface{}) (DeleteFile, error) {
+	switch f := fileFace.(type) {
+	case DeleteFile:
+		return f, nil
+	case *DeleteFile:
+		if f != nil {
+			return *f, nil
+		}
+	case File:
+		return DeleteFile{Filename: f.Filename, Options: f.Options}, nil
+	case *File:
+		if f != nil {
+			return DeleteFile{Filename: f.Filename, Options: f.Options}, nil
+		}
+	}
+	return DeleteFile{}, &errors.BifrostError{
+		Err:       fmt.Errorf("argument must be of type bifrost.DeleteFile"),
+		ErrorCode: errors.ErrBadRequest,
+	}
+}
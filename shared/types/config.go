@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type BridgeConfig struct {
 	// Provider is the name of the cloud storage service to use.
 	Provider Provider
@@ -12,10 +14,36 @@ type BridgeConfig struct {
 	// CredentialsFile is the path to the credentials file.
 	// This is only implemented by some providers (e.g. Google Cloud Storage).
 	CredentialsFile string
+	// Root is the directory files are stored under, with DefaultBucket as a subdirectory.
+	// This is only implemented by some providers (e.g. Local Storage).
+	Root string
+	// BaseURL is the URL that files are served from, used to build the URL and Preview of uploaded files.
+	// For Local Storage and Memory Storage it is the URL of Root, for S3 and Azure Blob Storage it is the URL of DefaultBucket
+	// (e.g. a CDN or the public URL of a Cloudflare R2 bucket).
+	// For Wasabi it defaults to the public URL of DefaultBucket.
+	// This is only implemented by some providers (e.g. Local Storage, Memory Storage, S3, Wasabi, Azure Blob Storage).
+	BaseURL string
+	// Endpoint is the URL of an S3-compatible service to use instead of AWS (e.g. MinIO, Cloudflare R2, DigitalOcean
+	// Spaces). The region defaults to us-east-1 when an endpoint is set without one.
+	// For Azure Blob Storage it is the URL of the blob service, such as http://127.0.0.1:10000/devstoreaccount1 for Azurite.
+	// This is only implemented by some providers (e.g. S3, Azure Blob Storage).
+	Endpoint string
+	// ForcePathStyle addresses buckets in the URL path (https://endpoint/bucket/key) instead of the host name
+	// (https://bucket.endpoint/key), as required by MinIO and most on-prem services.
+	// This is only implemented by some providers (e.g. S3).
+	ForcePathStyle bool
 	// SecretKey is the secret key for IAM authentication.
+	// For Azure Blob Storage it is the shared key of the storage account.
 	SecretKey string
 	// AccessKey is the access key for IAM authentication.
+	// For Azure Blob Storage it is the name of the storage account.
 	AccessKey string
+	// SASToken is a shared access signature to authenticate with instead of the account key.
+	// This is only implemented by some providers (e.g. Azure Blob Storage).
+	SASToken string
+	// ConnectionString is a storage account connection string to authenticate with instead of the account name and key.
+	// This is only implemented by some providers (e.g. Azure Blob Storage).
+	ConnectionString string
 	// Region is the service region to use for storing.
 	// This is only implemented by some providers (e.g. S3, Google Cloud Storage).
 	Region string
@@ -30,8 +58,37 @@ type BridgeConfig struct {
 	PublicRead bool
 	// UseAsync enables asynchronous operations with go routines.
 	UseAsync bool
+	// MaxConcurrency is the maximum number of concurrent operations when UseAsync is enabled (e.g. uploads in UploadMultiFile).
+	// It defaults to 10 when not set.
+	MaxConcurrency int
+	// MultipartThreshold is the size in bytes from which uploads are split into a multipart upload. It defaults to 100 MiB.
+	// Streams of unknown length are always uploaded in parts.
+	// This is only implemented by some providers (e.g. S3, Wasabi, Azure Blob Storage).
+	MultipartThreshold int64
+	// PartSize is the size in bytes of each part of a multipart upload. It defaults to 5 MiB, the smallest size S3 allows.
+	// This is only implemented by some providers (e.g. S3, Wasabi, Azure Blob Storage).
+	PartSize int64
+	// PartConcurrency is the number of parts of a multipart upload that are uploaded at once. It defaults to 5.
+	// This is only implemented by some providers (e.g. S3, Wasabi, Azure Blob Storage).
+	PartConcurrency int
+	// RetryPolicy configures how uploads failing with a transient error (e.g. a dropped connection or a 503) are retried.
+	// Uploads are not retried when it is nil.
+	// On S3 and Wasabi it replaces the retries of the SDK for uploads that can be sent again, rather than adding to them.
+	RetryPolicy *RetryPolicy
 	// PinataJWT is the JWT generated for your Pinata cloud account
 	PinataJWT string
+	// PinataAPIKey is the API key generated for your Pinata cloud account, sent as pinata_api_key.
+	// It is used along with PinataSecretAPIKey when PinataJWT is not set.
+	PinataAPIKey string
+	// PinataSecretAPIKey is the API secret generated along with PinataAPIKey, sent as pinata_secret_api_key.
+	PinataSecretAPIKey string
+	// VerifyCID checks that the CID returned for every uploaded file matches the CID computed locally from its content.
+	// A mismatch is returned as an ErrIntegrity error.
+	// This is only implemented by some providers (e.g. Pinata Cloud).
+	VerifyCID bool
+	// PinPollInterval is the delay between two polls of a pin job waiting for a CID to be pinned. It defaults to 2s.
+	// This is only implemented by some providers (e.g. Pinata Cloud).
+	PinPollInterval time.Duration
 	// Buckets specifics the list of bucket names to interact with
 	Buckets []string
 	// Object specifics an object name in a bucket to interact with
@@ -31,6 +31,9 @@ type UploadedFile struct {
 	CID string
 	// Error is the error returned by the provider. This is only used for async operations and multi file uploads.
 	Error error
+	// Provider is the provider that stored the file.
+	// This is only set by bridges composed of several providers (e.g. failover and replica).
+	Provider Provider
 }
 
 // Param is the struct used to pass parameters to request methods.
@@ -69,6 +72,9 @@ type MultiFile struct {
 	// GlobalOptions is a map of options to store along with all the files.
 	// say 3 of 4 files need to share the same option, you can set globally for those 3 files and set the 4th file's option separately, bifrost won't override the option
 	GlobalOptions map[string]interface{} `json:"global_options"`
+	// Progress, if set, is called with the aggregate progress of all files each time one of them makes progress and
+	// each time one of them finishes uploading.
+	Progress BatchProgressFunc `json:"-"`
 }
 
 // Validate validates the MultiFile struct.
@@ -84,6 +90,20 @@ func (m *MultiFile) Validate() error {
 	return nil
 }
 
+// Merge returns a copy of the i-th file with the global options merged into a fresh copy of its options.
+// Options already set on the file are not overridden, and neither the file's nor the global options map is written to,
+// which makes Merge safe to call concurrently.
+func (m *MultiFile) Merge(i int) File {
+	file := m.Files[i].Clone()
+	for k, v := range m.GlobalOptions {
+		// don't override if file has option already
+		if _, ok := file.Options[k]; !ok {
+			file.Options[k] = v
+		}
+	}
+	return file
+}
+
 // File is the struct for uploading a single file.
 type File struct {
 	// Handle is the handle to the file.
@@ -94,6 +114,8 @@ type File struct {
 	Filename string `json:"filename"`
 	// Options is a map of options to store along with each file.
 	Options map[string]interface{} `json:"options"`
+	// Progress, if set, is called with the progress of the upload as the file is sent to the provider.
+	Progress ProgressFunc `json:"-"`
 }
 
 // Validate validates the File struct.
@@ -110,14 +132,36 @@ func (f *File) Validate() error {
 	return nil
 }
 
-// DeleeFile is the struct for Deleting a single file.
+// Clone returns a copy of the file with its own, never nil, options map.
+// The option values themselves are not copied.
+func (f File) Clone() File {
+	options := make(map[string]interface{}, len(f.Options))
+	for k, v := range f.Options {
+		options[k] = v
+	}
+	f.Options = options
+	return f
+}
+
+// DeleteFile is the struct for deleting a single file.
 type DeleteFile struct {
 	// Handle is the handle to the file.
 	Handle io.Reader
-	// Filename is the name stored with the provider.
+	// Filename is the name stored with the provider. It is required by every provider but Pinata Cloud.
 	Filename string `json:"filename"`
-	// Buckets is the name stored with the provider.
+	// Buckets is the list of buckets to delete the file from. If empty, the default bucket is used.
 	Buckets []string `json:"buckets"`
+	// CID is the content identifier of the file.
+	// This is only implemented by some providers (e.g. Pinata Cloud), where it takes precedence over Filename.
+	CID string `json:"cid"`
 	// Options is a map of options to store along with each file.
 	Options map[string]interface{} `json:"options"`
 }
+
+// Validate validates the DeleteFile struct.
+func (d *DeleteFile) Validate() error {
+	if d.Filename == "" && d.CID == "" {
+		return errors.New("file.Filename or file.CID is required")
+	}
+	return nil
+}
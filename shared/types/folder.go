@@ -0,0 +1,42 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"path"
+)
+
+// Folder is the struct for uploading a local folder.
+// Every file found under Path is uploaded with its path relative to Path as its name, optionally behind Prefix.
+type Folder struct {
+	// Path is the path to the local folder to upload.
+	Path string `json:"path"`
+	// Prefix is the key prefix to store the files under with the provider (e.g. "assets/" stores "img/a.png" as "assets/img/a.png").
+	Prefix string `json:"prefix"`
+	// Include is a list of glob patterns a file must match to be uploaded. All files are included when empty.
+	// Patterns without a slash are matched against the file name, others against the path relative to Path. "**" matches any number of directories.
+	Include []string `json:"include"`
+	// Exclude is a list of glob patterns for files and directories that should not be uploaded. It follows the same rules as Include.
+	Exclude []string `json:"exclude"`
+	// IgnoreFiles is a list of .gitignore-style file names (e.g. ".gitignore") that are read from every directory walked.
+	IgnoreFiles []string `json:"ignore_files"`
+	// FollowSymlinks enables following symbolic links to files and directories. Symbolic links are skipped when false.
+	FollowSymlinks bool `json:"follow_symlinks"`
+	// Options is a map of options to store along with all the files, merged the same way as MultiFile.GlobalOptions.
+	Options map[string]interface{} `json:"options"`
+}
+
+// Validate validates the Folder struct.
+func (f *Folder) Validate() error {
+	if f.Path == "" {
+		return errors.New("folder.Path is required")
+	}
+	for _, patterns := range [][]string{f.Include, f.Exclude} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid folder pattern %q: %s", pattern, err.Error())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/opensaucerer/bifrost/shared/config"
+)
+
+// JSONFile is the struct for uploading a Go value as a JSON document.
+type JSONFile struct {
+	// Data is the value to upload. It is marshalled with encoding/json.
+	Data interface{}
+	// Filename is the name to store the document as with the provider.
+	// Pinata Cloud uses it as the pin name unless a name is set in the pinataMetadata option.
+	Filename string
+	// Options is a map of options to store along with the document, as for File.
+	// The content type defaults to application/json.
+	Options map[string]interface{}
+}
+
+// Validate validates the JSONFile struct.
+func (j *JSONFile) Validate() error {
+	if j.Data == nil {
+		return errors.New("json.Data is required")
+	}
+	return nil
+}
+
+// File marshals the document and returns it as a File with the application/json content type, unless another content
+// type is set in the options.
+func (j JSONFile) File() (File, error) {
+	if err := j.Validate(); err != nil {
+		return File{}, err
+	}
+	b, err := json.Marshal(j.Data)
+	if err != nil {
+		return File{}, err
+	}
+
+	file := File{
+		Handle:   bytes.NewReader(b),
+		Filename: j.Filename,
+		Options:  j.Options,
+	}.Clone()
+	if _, ok := file.Options[config.OptContentType]; !ok {
+		file.Options[config.OptContentType] = config.ReqJSON
+	}
+	return file, file.Validate()
+}
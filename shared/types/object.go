@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"time"
+)
+
+// ObjectInfo is the provider-neutral description of a file stored with a provider.
+type ObjectInfo struct {
+	// Name is the name of the file with the provider.
+	Name string
+	// Bucket is the bucket the file is stored in.
+	Bucket string
+	// Size is the size of the file in bytes. It is -1 when the provider does not report it.
+	Size int64
+	// ContentType is the MIME type of the file.
+	ContentType string
+	// Metadata is the user metadata stored along with the file.
+	Metadata map[string]string
+	// ETag is the entity tag or checksum of the file as reported by the provider.
+	ETag string
+	// CID is the content identifier for the file.
+	// This is only implemented by some providers (e.g. Pinata Cloud).
+	CID string
+	// Created is the time the file was created with the provider, if known.
+	Created time.Time
+	// Updated is the time the file was last modified with the provider, if known.
+	Updated time.Time
+	// URL is the public location of the file in the cloud.
+	URL string
+	// ProviderObject is the object returned by the cloud storage provider.
+	// You need to type assert this to the correct type to use it.
+	ProviderObject interface{}
+}
+
+// ListOptions is the struct for listing the files in a bucket.
+type ListOptions struct {
+	// Prefix limits the listing to files whose name begins with it.
+	Prefix string `json:"prefix"`
+	// Delimiter groups the names that contain it after Prefix into ListResult.Prefixes, like directories (e.g. "/").
+	// This is only implemented by some providers (e.g. S3, Google Cloud Storage, Azure Blob Storage).
+	Delimiter string `json:"delimiter"`
+	// PageSize is the maximum number of files to return. The provider default is used when it is 0.
+	PageSize int `json:"page_size"`
+	// ContinuationToken is the ListResult.NextToken of the previous page. Leave it empty to list the first page.
+	ContinuationToken string `json:"continuation_token"`
+}
+
+// Validate validates the ListOptions struct.
+func (l *ListOptions) Validate() error {
+	if l.PageSize < 0 {
+		return errors.New("listOptions.PageSize cannot be negative")
+	}
+	return nil
+}
+
+// ListResult is the struct representing a page of listed files.
+type ListResult struct {
+	// Files is the list of files in the page.
+	Files []*ObjectInfo
+	// Prefixes is the list of names grouped by ListOptions.Delimiter.
+	Prefixes []string
+	// NextToken is the token to pass as ListOptions.ContinuationToken to list the next page. It is empty on the last page.
+	NextToken string
+}
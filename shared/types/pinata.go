@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // PinataAuthResponse is the response from Pinata Cloud when authenticating.
 type PinataAuthResponse struct {
 	Error struct {
@@ -16,3 +18,98 @@ type PinataPinFileResponse struct {
 	PinSize   int64  `json:"PinSize"`
 	Error     string `json:"error"`
 }
+
+// PinataErrorResponse is the error body returned by Pinata Cloud when a request fails.
+type PinataErrorResponse struct {
+	Error struct {
+		Reason  string `json:"reason"`
+		Details string `json:"details"`
+	} `json:"error"`
+}
+
+// PinataPinListResponse is the response from Pinata Cloud when listing pins.
+type PinataPinListResponse struct {
+	Count int64       `json:"count"`
+	Rows  []PinataPin `json:"rows"`
+}
+
+// PinataPin is a single pin returned by Pinata Cloud when listing pins.
+type PinataPin struct {
+	ID           string    `json:"id"`
+	IpfsPinHash  string    `json:"ipfs_pin_hash"`
+	Size         int64     `json:"size"`
+	DatePinned   time.Time `json:"date_pinned"`
+	DateUnpinned time.Time `json:"date_unpinned"`
+	MimeType     string    `json:"mime_type"`
+	Metadata     struct {
+		Name      string                 `json:"name"`
+		KeyValues map[string]interface{} `json:"keyvalues"`
+	} `json:"metadata"`
+}
+
+// PinataPinByHashResponse is the response from Pinata Cloud when queueing a CID to be pinned.
+type PinataPinByHashResponse struct {
+	ID       string `json:"id"`
+	IpfsHash string `json:"ipfsHash"`
+	Status   string `json:"status"`
+	Name     string `json:"name"`
+}
+
+// PinataPinJobsResponse is the response from Pinata Cloud when listing pin by CID jobs.
+type PinataPinJobsResponse struct {
+	Count int64          `json:"count"`
+	Rows  []PinataPinJob `json:"rows"`
+}
+
+// PinataPinJob is a single pin by CID job returned by Pinata Cloud when listing pin jobs.
+type PinataPinJob struct {
+	ID          string    `json:"id"`
+	IpfsPinHash string    `json:"ipfs_pin_hash"`
+	DateQueued  time.Time `json:"date_queued"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+}
+
+// PinStatus is the status of a pin by CID job.
+type PinStatus string
+
+// Pin statuses reported by Pinata Cloud for pin by CID jobs.
+const (
+	// PinStatusPrechecking is the status of a job that Pinata is checking before searching for the content.
+	PinStatusPrechecking PinStatus = "prechecking"
+	// PinStatusSearching is the status of a job that Pinata is searching the IPFS network for.
+	PinStatusSearching PinStatus = "searching"
+	// PinStatusRetrieving is the status of a job whose content Pinata has found and is retrieving.
+	PinStatusRetrieving PinStatus = "retrieving"
+	// PinStatusPinned is the status of a job whose content is pinned by the account.
+	PinStatusPinned PinStatus = "pinned"
+	// PinStatusExpired is the status of a job whose content could not be found within Pinata's search window.
+	PinStatusExpired PinStatus = "expired"
+	// PinStatusOverFreeLimit is the status of a job that would take the account over the limits of the free plan.
+	PinStatusOverFreeLimit PinStatus = "over_free_limit"
+	// PinStatusOverMaxSize is the status of a job whose content is larger than the account allows.
+	PinStatusOverMaxSize PinStatus = "over_max_size"
+	// PinStatusInvalidObject is the status of a job whose CID does not point to a valid object.
+	PinStatusInvalidObject PinStatus = "invalid_object"
+	// PinStatusBadHostNode is the status of a job whose host nodes could not be reached.
+	PinStatusBadHostNode PinStatus = "bad_host_node"
+)
+
+// Pinned returns true if the content of the job is pinned.
+func (s PinStatus) Pinned() bool {
+	return s == PinStatusPinned
+}
+
+// Failed returns true if the job stopped without pinning its content.
+func (s PinStatus) Failed() bool {
+	switch s {
+	case PinStatusExpired, PinStatusOverFreeLimit, PinStatusOverMaxSize, PinStatusInvalidObject, PinStatusBadHostNode:
+		return true
+	}
+	return false
+}
+
+// Done returns true if the job is pinned or failed, i.e. its status will not change anymore.
+func (s PinStatus) Done() bool {
+	return s.Pinned() || s.Failed()
+}
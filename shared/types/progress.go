@@ -0,0 +1,40 @@
+package types
+
+// Progress describes how much of a file has been sent to the provider.
+type Progress struct {
+	// Name is the name the file is stored as with the provider.
+	Name string
+	// Path is the local path to the file, if it was uploaded from one.
+	Path string
+	// Bytes is the number of bytes of the file sent so far. It can move backwards when a provider rewinds the file to
+	// send it again.
+	Bytes int64
+	// Total is the size of the file in bytes, or -1 if it is not known (e.g. for a Handle that is not seekable).
+	Total int64
+}
+
+// ProgressFunc is called with the progress of an upload as the file is read.
+// It is called from the uploading goroutine and should return quickly.
+type ProgressFunc func(Progress)
+
+// BatchProgress describes the progress of an UploadMultiFile call.
+type BatchProgress struct {
+	// File is the progress of the file that caused the event.
+	File Progress
+	// Index is the index of that file in MultiFile.Files.
+	Index int
+	// Bytes is the number of bytes sent across all files.
+	Bytes int64
+	// Total is the size of all files in bytes, or -1 if the size of any file is not known.
+	Total int64
+	// Completed is the number of files that have finished uploading, successfully or not.
+	Completed int
+	// Failed is the number of files that failed to upload.
+	Failed int
+	// Files is the number of files in the batch.
+	Files int
+}
+
+// BatchProgressFunc is called with the aggregate progress of an UploadMultiFile call.
+// Calls are serialized, even when files are uploaded concurrently, and should return quickly.
+type BatchProgressFunc func(BatchProgress)
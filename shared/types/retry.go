@@ -0,0 +1,21 @@
+package types
+
+import "time"
+
+// RetryPolicy configures how failed uploads are retried.
+//
+// The delay before the n-th retry is BaseDelay * 2^(n-1), capped at MaxDelay and shortened by a random fraction of up
+// to Jitter. Only errors classified as transient are retried, and a Handle is only retried if it can be rewound
+// (i.e. it is an io.Seeker).
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It defaults to 200ms.
+	BaseDelay time.Duration
+	// MaxDelay is the longest delay between two attempts. It defaults to 10s.
+	MaxDelay time.Duration
+	// Jitter is the fraction, between 0 and 1, of each delay that is randomized to spread out retries.
+	Jitter float64
+	// Retryable, if set, replaces the provider's classification of which errors are transient.
+	Retryable func(err error) bool
+}
@@ -0,0 +1,142 @@
+package walk
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+)
+
+// rule is a single pattern read from a .gitignore-style file.
+type rule struct {
+	// pattern is the glob pattern with any leading "!", leading "/" and trailing "/" removed.
+	pattern string
+	// base is the slash-separated directory, relative to the walked root, that holds the ignore file.
+	base string
+	// negate re-includes paths matched by an earlier rule.
+	negate bool
+	// dirOnly restricts the rule to directories.
+	dirOnly bool
+	// anchored matches the pattern against the path relative to base instead of any path segment.
+	anchored bool
+}
+
+// matches reports whether the rule matches the slash-separated path rel, relative to the walked root.
+func (r rule) matches(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = rel[len(r.base)+1:]
+	}
+	if r.anchored {
+		return match(r.pattern, rel)
+	}
+	return match("**/"+r.pattern, rel)
+}
+
+// readRules parses the .gitignore-style file at name. base is the directory holding the file, relative to the walked root.
+func readRules(name, base string) ([]rule, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []rule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := rule{base: base}
+		switch {
+		case strings.HasPrefix(line, "!"):
+			r.negate = true
+			line = line[1:]
+		case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.HasPrefix(line, "/") {
+			r.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		// a slash anywhere but the end anchors the pattern to the ignore file's directory
+		if strings.Contains(line, "/") {
+			r.anchored = true
+		}
+		// malformed patterns never match, so they are dropped
+		if _, err := path.Match(line, ""); line == "" || err != nil {
+			continue
+		}
+		r.pattern = line
+		rules = append(rules, r)
+	}
+	return rules, scanner.Err()
+}
+
+// ignored reports whether rel is ignored by rules. As with git, the last matching rule wins.
+func ignored(rules []rule, rel string, isDir bool) bool {
+	ignore := false
+	for _, r := range rules {
+		if r.matches(rel, isDir) {
+			ignore = !r.negate
+		}
+	}
+	return ignore
+}
+
+// matchAny reports whether rel matches any of the include/exclude style patterns.
+// Patterns without a slash are matched against the base name of rel.
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		p = strings.TrimPrefix(p, "/")
+		if !strings.Contains(p, "/") {
+			if match(p, path.Base(rel)) {
+				return true
+			}
+			continue
+		}
+		if match(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether the slash-separated name matches pattern, where a "**" segment matches zero or more path segments.
+func match(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
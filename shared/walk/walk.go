@@ -0,0 +1,125 @@
+// Package walk resolves a bifrost folder into the list of files to upload.
+package walk
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// walker holds the state of a single folder walk.
+type walker struct {
+	folder types.Folder
+	files  []types.File
+	// visited holds the resolved directories on the current branch of the walk, to break symbolic link cycles.
+	visited map[string]bool
+}
+
+/*
+Files walks folder.Path and returns a types.File for every file that should be uploaded.
+
+Each file's Filename is its slash-separated path relative to folder.Path, behind folder.Prefix.
+Directories matched by folder.Exclude or an ignore file are not descended into.
+*/
+func Files(folder types.Folder) ([]types.File, error) {
+	info, err := os.Stat(folder.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", folder.Path)
+	}
+
+	w := &walker{folder: folder, visited: map[string]bool{}}
+	if err := w.walk(folder.Path, "", nil); err != nil {
+		return nil, err
+	}
+	return w.files, nil
+}
+
+// walk visits the directory dir, whose path relative to the root is rel, with the ignore rules inherited from its parents.
+func (w *walker) walk(dir, rel string, rules []rule) error {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if w.visited[real] {
+		return nil
+	}
+	w.visited[real] = true
+	defer delete(w.visited, real)
+
+	// rules read in this directory take precedence over the inherited ones
+	for _, name := range w.folder.IgnoreFiles {
+		r, err := readRules(filepath.Join(dir, name), rel)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		rules = append(rules[:len(rules):len(rules)], r...)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := filepath.Join(dir, entry.Name())
+		entryRel := path.Join(rel, entry.Name())
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if !w.folder.FollowSymlinks {
+				continue
+			}
+			if info, err = os.Stat(name); err != nil {
+				return err
+			}
+		}
+
+		if info.IsDir() {
+			if matchAny(w.folder.Exclude, entryRel) || ignored(rules, entryRel, true) {
+				continue
+			}
+			if err := w.walk(name, entryRel, rules); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		if len(w.folder.Include) > 0 && !matchAny(w.folder.Include, entryRel) {
+			continue
+		}
+		if matchAny(w.folder.Exclude, entryRel) || ignored(rules, entryRel, false) {
+			continue
+		}
+
+		w.files = append(w.files, types.File{
+			Path:     name,
+			Filename: key(w.folder.Prefix, entryRel),
+			Options:  map[string]interface{}{},
+		})
+	}
+	return nil
+}
+
+// key joins the remote prefix and the relative path of a file.
+func key(prefix, rel string) string {
+	if prefix == "" {
+		return rel
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + rel
+}
@@ -0,0 +1,150 @@
+package walk
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/opensaucerer/bifrost/shared/types"
+)
+
+// tree creates the files of a folder under a temporary directory, along with a symbolic link to a file and a symbolic
+// link cycle, and returns its path.
+func tree(t *testing.T) string {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":     "*.log\n!keep.log\nbuild/\n/top.txt\n",
+		"a.txt":          "a",
+		"b.go":           "b",
+		"top.txt":        "top",
+		"keep.log":       "keep",
+		"x.log":          "x",
+		"build/out.bin":  "out",
+		"other/f.go":     "f",
+		"sub/.gitignore": "*.go\n!z.log\n",
+		"sub/build":      "a file named like an ignored directory",
+		"sub/top.txt":    "top",
+		"sub/y.log":      "y",
+		"sub/z.log":      "z",
+		"sub/c.go":       "c",
+		"sub/deep/d.txt": "d",
+		"sub/deep/e.go":  "e",
+	}
+	for name, data := range files {
+		name = filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(root, "a.txt"), filepath.Join(root, "link.txt")); err != nil {
+		t.Skipf("Symbolic links are not supported: %v", err)
+	}
+	if err := os.Symlink(root, filepath.Join(root, "sub", "deep", "loop")); err != nil {
+		t.Skipf("Symbolic links are not supported: %v", err)
+	}
+	return root
+}
+
+func TestFiles(t *testing.T) {
+	cases := []struct {
+		name   string
+		folder types.Folder
+		want   []string
+	}{
+		{
+			name: "every file",
+			want: []string{
+				".gitignore", "a.txt", "b.go", "build/out.bin", "keep.log", "other/f.go", "sub/.gitignore", "sub/build",
+				"sub/c.go", "sub/deep/d.txt", "sub/deep/e.go", "sub/top.txt", "sub/y.log", "sub/z.log", "top.txt", "x.log",
+			},
+		},
+		{
+			// negated, directory-only and anchored rules, with the rules of sub/.gitignore only applying under sub
+			name:   "ignore files",
+			folder: types.Folder{IgnoreFiles: []string{".gitignore"}},
+			want: []string{
+				".gitignore", "a.txt", "b.go", "keep.log", "other/f.go", "sub/.gitignore", "sub/build", "sub/deep/d.txt",
+				"sub/top.txt", "sub/z.log",
+			},
+		},
+		{
+			name:   "include with **",
+			folder: types.Folder{Include: []string{"sub/**/*.txt"}},
+			want:   []string{"sub/deep/d.txt", "sub/top.txt"},
+		},
+		{
+			name:   "include by file name",
+			folder: types.Folder{Include: []string{"*.go"}},
+			want:   []string{"b.go", "other/f.go", "sub/c.go", "sub/deep/e.go"},
+		},
+		{
+			name:   "exclude with a prefix",
+			folder: types.Folder{Prefix: "assets/", Exclude: []string{"sub", "*.log"}},
+			want: []string{
+				"assets/.gitignore", "assets/a.txt", "assets/b.go", "assets/build/out.bin", "assets/other/f.go",
+				"assets/top.txt",
+			},
+		},
+		{
+			name:   "include and exclude",
+			folder: types.Folder{Include: []string{"**/*.txt"}, Exclude: []string{"sub/deep/**"}},
+			want:   []string{"a.txt", "sub/top.txt", "top.txt"},
+		},
+		{
+			// sub/deep/loop links back to the root, which is not walked again
+			name:   "follow symbolic links",
+			folder: types.Folder{Include: []string{"*.txt"}, FollowSymlinks: true},
+			want:   []string{"a.txt", "link.txt", "sub/deep/d.txt", "sub/top.txt", "top.txt"},
+		},
+	}
+
+	root := tree(t)
+	for _, c := range cases {
+		t.Run("Tests Files method with "+c.name, func(t *testing.T) {
+			c.folder.Path = root
+			files, err := Files(c.folder)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got := make([]string, len(files))
+			for i, file := range files {
+				got[i] = file.Filename
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("Expected %v, got %v", c.want, got)
+			}
+		})
+	}
+
+	t.Run("Tests Files method with a file", func(t *testing.T) {
+		if _, err := Files(types.Folder{Path: filepath.Join(root, "a.txt")}); err == nil {
+			t.Errorf("Expected a file to be rejected")
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		folder types.Folder
+		valid  bool
+	}{
+		{name: "valid patterns", folder: types.Folder{Path: ".", Include: []string{"**/*.go"}, Exclude: []string{"[a-z]*"}}, valid: true},
+		{name: "an invalid include", folder: types.Folder{Path: ".", Include: []string{"[a-"}}},
+		{name: "an invalid exclude", folder: types.Folder{Path: ".", Exclude: []string{"sub/\\"}}},
+		{name: "no path", folder: types.Folder{}},
+	}
+	for _, c := range cases {
+		t.Run("Tests Folder.Validate method with "+c.name, func(t *testing.T) {
+			if err := c.folder.Validate(); (err == nil) != c.valid {
+				t.Errorf("Expected valid to be %t, got %v", c.valid, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package bifrost
 
-import "github.com/opensaucerer/bifrost/shared/types"
+import (
+	"context"
+	"io"
+
+	"github.com/opensaucerer/bifrost/shared/types"
+)
 
 /*
 At a point, you might wonder why we have some structs and constants duplicated in the root package and in the subpackages.
@@ -15,42 +20,145 @@ It's just a design choice, others might oppose it, that's fine. But keeping the
 // BridgeConfig is the configuration for the rainbow bridge.
 type BridgeConfig types.BridgeConfig
 
+// Provider is the identifier of a provider, as set in BridgeConfig.Provider and passed to RegisterProvider.
+type Provider = types.Provider
+
 type RainbowBridge interface {
 	/*
 		UploadFile uploads a file to the provider storage and returns an error if one occurs.
+		fileFace must be of type bifrost.File or *bifrost.File. Prefer Upload for compile-time type checking.
 
 		Note: for some providers, UploadFile requires that a default bucket be set in bifrost.BridgeConfig.
 	*/
 	UploadFile(fileFace interface{}) (*types.UploadedFile, error)
+	// UploadFileContext is like UploadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+	UploadFileContext(ctx context.Context, fileFace interface{}) (*types.UploadedFile, error)
 	/*
 		UploadMultiFile uploads mutliple files to the provider storage and returns an error if one occurs. If any of the uploads fail, the error is appended
 		to the []UploadedFile.Error and also logged when debug is enabled while the rest of the uploads continue.
+		multiFace must be of type bifrost.MultiFile or *bifrost.MultiFile. Prefer UploadMulti for compile-time type checking.
 
 		Note: for some providers, UploadMultiFile requires that a default bucket be set in bifrost.BridgeConfig.
 	*/
 	UploadMultiFile(multiFace interface{}) ([]*types.UploadedFile, error)
+	// UploadMultiFileContext is like UploadMultiFile but uses ctx for cancellation and deadlines of every upload.
+	UploadMultiFileContext(ctx context.Context, multiFace interface{}) ([]*types.UploadedFile, error)
 	/*
 		Disconnect closes the provider client connection and returns an error if one occurs.
 
 		Disconnect should only be called when the connection is no longer needed.
 	*/
 	Disconnect() error
+	/*
+		OpenReader opens a file in the provider storage for reading and returns its contents along with its attributes.
+		If the file does not exist, an error with the code ErrNotFound is returned. The caller must close the returned reader.
+
+		Note: for some providers, OpenReader requires that a default bucket be set in bifrost.BridgeConfig.
+	*/
+	OpenReader(name string) (io.ReadCloser, *types.ObjectInfo, error)
+	// OpenReaderContext is like OpenReader but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+	OpenReaderContext(ctx context.Context, name string) (io.ReadCloser, *types.ObjectInfo, error)
+	/*
+		DownloadFile downloads a file from the provider storage to localPath and returns its attributes.
+		localPath is only replaced once the whole file has been downloaded.
+
+		Note: for some providers, DownloadFile requires that a default bucket be set in bifrost.BridgeConfig.
+	*/
+	DownloadFile(name, localPath string) (*types.ObjectInfo, error)
+	// DownloadFileContext is like DownloadFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+	DownloadFileContext(ctx context.Context, name, localPath string) (*types.ObjectInfo, error)
+	/*
+		ListFiles lists the files in the provider storage one page at a time and returns an error if one occurs.
+		Pass ListResult.NextToken as ListOptions.ContinuationToken to list the following page.
+
+		Note: for some providers, ListFiles requires that a default bucket be set in bifrost.BridgeConfig.
+	*/
+	ListFiles(opts types.ListOptions) (*types.ListResult, error)
+	// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+	ListFilesContext(ctx context.Context, opts types.ListOptions) (*types.ListResult, error)
+	/*
+		StatFile returns the attributes of a file in the provider storage and returns an error if one occurs.
+		If the file does not exist, an error with the code ErrNotFound is returned.
+
+		Note: for some providers, StatFile requires that a default bucket be set in bifrost.BridgeConfig.
+	*/
+	StatFile(name string) (*types.ObjectInfo, error)
+	// StatFileContext is like StatFile but uses ctx for cancellation and deadlines.
+	StatFileContext(ctx context.Context, name string) (*types.ObjectInfo, error)
+	// Exists returns true if a file exists in the provider storage and returns an error if the lookup fails.
+	Exists(name string) (bool, error)
+	// ExistsContext is like Exists but uses ctx for cancellation and deadlines.
+	ExistsContext(ctx context.Context, name string) (bool, error)
 	// Config returns the provider configuration.
 	Config() *types.BridgeConfig
 	// IsConnected returns true if there is an active connection to the provider.
 	IsConnected() bool
 	/*
-		UploadFolder uploads a folder to the provider storage and returns an error if one occurs.
+		UploadFolder uploads every file in a local folder to the provider storage and returns an error if one occurs.
+		foldFace must be of type bifrost.Folder or *bifrost.Folder. Prefer UploadDir for compile-time type checking. Like UploadMultiFile, one UploadedFile is returned per file with any failure
+		recorded in its Error field.
 
 		Note: for some providers, UploadFolder requires that a default bucket be set in bifrost.BridgeConfig.
 	*/
 	UploadFolder(foldFace interface{}) ([]*types.UploadedFile, error)
+	// UploadFolderContext is like UploadFolder but uses ctx for cancellation and deadlines of every upload.
+	UploadFolderContext(ctx context.Context, foldFace interface{}) ([]*types.UploadedFile, error)
 	/*
-	DeleteFile deletes a file from a bucket in provider's storage and returns an error if one occurs.
+		DeleteFile deletes a file from a bucket in provider's storage and returns an error if one occurs.
+		fileFace must be of type bifrost.DeleteFile or *bifrost.DeleteFile. Prefer Delete for compile-time type checking. If the file does not exist, an error with the code ErrNotFound is returned.
 
-	Note: DeleteFile requires that a default bucket be set in bifrost.BridgeConfig.
+		Note: DeleteFile requires that a default bucket be set in bifrost.BridgeConfig unless DeleteFile.Buckets is set.
 	*/
 	DeleteFile(fileFace interface{}) error
+	// DeleteFileContext is like DeleteFile but uses ctx for cancellation and deadlines. DefaultTimeout, when set, still applies on top of ctx.
+	DeleteFileContext(ctx context.Context, fileFace interface{}) error
+	/*
+		Upload uploads a file to the provider storage and returns an error if one occurs.
+		It is the typed equivalent of UploadFileContext, which is a thin adapter over it.
+	*/
+	Upload(ctx context.Context, file types.File) (*types.UploadedFile, error)
+	/*
+		UploadMulti uploads multiple files to the provider storage and returns an error if one occurs.
+		It is the typed equivalent of UploadMultiFileContext, which is a thin adapter over it.
+	*/
+	UploadMulti(ctx context.Context, multiFile types.MultiFile) ([]*types.UploadedFile, error)
+	/*
+		UploadDir uploads every file in a local folder to the provider storage and returns an error if one occurs.
+		It is the typed equivalent of UploadFolderContext, which is a thin adapter over it.
+	*/
+	UploadDir(ctx context.Context, folder types.Folder) ([]*types.UploadedFile, error)
+	/*
+		Delete deletes a file from the provider storage and returns an error if one occurs.
+		It is the typed equivalent of DeleteFileContext, which is a thin adapter over it.
+	*/
+	Delete(ctx context.Context, file types.DeleteFile) error
+}
+
+/*
+Pinger is implemented by rainbow bridges that can check that their provider is reachable with the configured credentials.
+Every built-in provider implements it, and composite bridges such as failover use it to probe the health of a bridge.
+
+	if pinger, ok := bridge.(bifrost.Pinger); ok {
+		err := pinger.Ping(ctx)
+	}
+*/
+type Pinger interface {
+	// Ping makes a lightweight authenticated call to the provider and returns an error if one occurs.
+	Ping(ctx context.Context) error
+}
+
+/*
+JSONUploader is implemented by rainbow bridges that can upload a Go value as a JSON document. Pinata Cloud pins the
+document with pinJSONToIPFS while the other providers store the marshalled document with the application/json content
+type.
+
+	if uploader, ok := bridge.(bifrost.JSONUploader); ok {
+		uploadedFile, err := uploader.UploadJSON(ctx, bifrost.JSONFile{Data: metadata, Filename: "1.json"})
+	}
+*/
+type JSONUploader interface {
+	// UploadJSON marshals doc.Data and uploads it as a JSON document and returns an error if one occurs.
+	UploadJSON(ctx context.Context, doc types.JSONFile) (*types.UploadedFile, error)
 }
 
 // BifrostError is the interface for errors returned by Bifrost.
@@ -62,9 +170,48 @@ type Error interface {
 // PinataPinFileResponse is the response from Pinata Cloud when pinning a file.
 type PinataPinFileResponse = types.PinataPinFileResponse
 
+// PinStatus is the status of a Pinata pin by CID job.
+type PinStatus = types.PinStatus
+
 // MultiFile is the struct for uploading multiple files.
 // Along with options, you can also set global options that will be applied to all files.
 type MultiFile = types.MultiFile
 
 // File is the struct for uploading a single file.
 type File = types.File
+
+// JSONFile is the struct for uploading a Go value as a JSON document.
+type JSONFile = types.JSONFile
+
+// DeleteFile is the struct for deleting a single file.
+type DeleteFile = types.DeleteFile
+
+// Folder is the struct for uploading a local folder.
+type Folder = types.Folder
+
+// ObjectInfo is the provider-neutral description of a file stored with a provider.
+type ObjectInfo = types.ObjectInfo
+
+// ListOptions is the struct for listing the files in a bucket.
+type ListOptions = types.ListOptions
+
+// ListResult is the struct representing a page of listed files.
+type ListResult = types.ListResult
+
+// UploadedFile is the struct representing a completed file/files upload.
+type UploadedFile = types.UploadedFile
+
+// Progress describes how much of a file has been sent to the provider.
+type Progress = types.Progress
+
+// ProgressFunc is the callback set on File.Progress to follow an upload.
+type ProgressFunc = types.ProgressFunc
+
+// BatchProgress describes the aggregate progress of an UploadMultiFile call.
+type BatchProgress = types.BatchProgress
+
+// BatchProgressFunc is the callback set on MultiFile.Progress to follow an UploadMultiFile call.
+type BatchProgressFunc = types.BatchProgressFunc
+
+// RetryPolicy configures how failed uploads are retried.
+type RetryPolicy = types.RetryPolicy
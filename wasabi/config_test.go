@@ -0,0 +1,93 @@
+package wasabi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/opensaucerer/bifrost"
+	"github.com/opensaucerer/bifrost/wasabi"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("Tests Config method points the bridge at the Wasabi endpoint", func(t *testing.T) {
+		bridge, err := bifrost.NewRainbowBridge(&bifrost.BridgeConfig{
+			DefaultBucket: "bucket",
+			Provider:      bifrost.WasabiCloudStorage,
+			AccessKey:     "key",
+			SecretKey:     "secret",
+			Region:        "eu-central-1",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer bridge.Disconnect()
+
+		c := bridge.Config()
+		if c.Provider != "Wasabi Cloud Storage" || c.Region != "eu-central-1" {
+			t.Errorf("Unexpected provider %s in region %s", c.Provider, c.Region)
+		}
+		if c.Endpoint != "https://s3.eu-central-1.wasabisys.com" || !c.ForcePathStyle {
+			t.Errorf("Expected path-style requests to the Wasabi endpoint, got %s (path style %t)", c.Endpoint, c.ForcePathStyle)
+		}
+		if c.BaseURL != "https://bucket.s3.eu-central-1.wasabisys.com/" {
+			t.Errorf("Expected files to be served from the Wasabi bucket URL, got %s", c.BaseURL)
+		}
+	})
+}
+
+func TestSharedConfig(t *testing.T) {
+	// signer returns the access key that requests of a bridge authenticated with the shared credentials are signed with
+	signer := func(t *testing.T, credentials string) string {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "credentials"), []byte(credentials), 0o600)
+		t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+		t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+		t.Setenv("AWS_PROFILE", "")
+		t.Setenv("AWS_ACCESS_KEY_ID", "")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+		t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+		bridge, err := bifrost.NewRainbowBridge(&bifrost.BridgeConfig{
+			DefaultBucket: "bucket",
+			Provider:      bifrost.WasabiCloudStorage,
+			Region:        "eu-central-1",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer bridge.Disconnect()
+
+		var authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+		}))
+		defer server.Close()
+		bridge.(*wasabi.WasabiCloudStorage).Client.HeadBucket(context.Background(), &s3.HeadBucketInput{Bucket: aws.String("bucket")}, func(o *s3.Options) {
+			o.EndpointResolver = s3.EndpointResolverFromURL(server.URL)
+		})
+		_, key, _ := strings.Cut(authorization, "Credential=")
+		key, _, _ = strings.Cut(key, "/")
+		return key
+	}
+
+	t.Run("Tests NewRainbowBridge method without a wasabi profile", func(t *testing.T) {
+		if key := signer(t, "[default]\naws_access_key_id = default\naws_secret_access_key = secret\n"); key != "default" {
+			t.Errorf("Expected the default profile to be used, got key %q", key)
+		}
+	})
+
+	t.Run("Tests NewRainbowBridge method with a wasabi profile", func(t *testing.T) {
+		key := signer(t, "[default]\naws_access_key_id = default\naws_secret_access_key = secret\n"+
+			"[wasabi]\naws_access_key_id = wasabi\naws_secret_access_key = secret\n")
+		if key != "wasabi" {
+			t.Errorf("Expected the wasabi profile to be used, got key %q", key)
+		}
+	})
+}